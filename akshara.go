@@ -3,33 +3,113 @@ package aksharamukha
 import (
 	"io"
 	"fmt"
+	"errors"
+	"syscall"
 	"net/http"
 	"net/url"
 	"strings"
 	"context"
+	"time"
 	
 	"github.com/gookit/color"
 	"github.com/k0kubun/pp"
 	iso "github.com/barbashov/iso639-3"
+	"golang.org/x/text/language"
 	
 	"github.com/tassa-yoniso-manasi-karoto/dockerutil"
 )
 
+// NativizeMode controls the backend's nativization according to output script conventions
+type NativizeMode int
+
+const (
+	// NativizeDefault leaves the backend default alone (nativization is on)
+	NativizeDefault NativizeMode = iota
+	NativizeOn
+	NativizeOff
+)
+
+func (m NativizeMode) String() string {
+	switch m {
+	case NativizeOn:
+		return "on"
+	case NativizeOff:
+		return "off"
+	}
+	return "default"
+}
+
+// NativizeFromBool maps the former boolean Nativize field onto a NativizeMode
+// with identical requests: false (including the zero value) sent nativize=false
+// and true left the backend default in place.
+func NativizeFromBool(nativize bool) NativizeMode {
+	if nativize {
+		return NativizeDefault
+	}
+	return NativizeOff
+}
+
 // TranslitOptions holds configuration for the transliteration process
 type TranslitOptions struct {
-	// If false, prevents automatic nativization according to output script conventions
-	Nativize bool
+	// Whether to nativize according to output script conventions, the zero value
+	// leaves the backend default (on)
+	Nativize NativizeMode
 	// Options applied before transliteration
 	PreOptions []string
 	// Options applied after transliteration
 	PostOptions []string
+	// If true, unknown or inapplicable options are reported to OnWarning and
+	// forwarded as is instead of failing the conversion. Options missing from the
+	// catalog can also be added with RegisterPreOption and RegisterPostOption.
+	Lenient bool
+	// Receives non-fatal problems, may be nil
+	OnWarning func(Warning)
+	// Warning kinds not handed to OnWarning, e.g. WarnIndicSubset for callers
+	// who know the backend only covers the Indic subset of Thai or Tibetan
+	SuppressWarnings []WarningKind
+	// ISO 639 code of the text if known, used to scope rules (set by Roman)
+	Language string
+	// Called for every rule that rewrote the output, may be nil (see WithRules)
+	TraceRules func(RuleTrace)
+	// Reports runs TranslitSpans should leave intact, may be nil. With a
+	// romanization scheme as source, Latin runs are offered word by word so
+	// that English words can be told from the scheme's own
+	SkipSpan func(Segment) bool
+	// If true, a request without a source script is sent with the one picked by
+	// DetectScript (see TranslitResult.Source) instead of leaving the backend
+	// to detect it
+	SendDetectedSource bool
+	// What to do with script pairs the capability matrix rates lossy or
+	// unsupported (see Supports), the zero value lets them through
+	PairPolicy PairPolicy
 }
 
+// PairPolicy controls conversions between scripts the capability matrix rates low
+type PairPolicy int
+
+const (
+	PairAllow PairPolicy = iota
+	// PairWarn reports low-quality pairs with WarnLowQualityPair
+	PairWarn
+	// PairRefuse fails the conversion of low-quality pairs
+	PairRefuse
+)
+
 // DefaultOptions returns the default transliteration options
 func DefaultOptions() TranslitOptions {
 	return TranslitOptions{}
 }
 
+// LegacyOptions returns opts with an unset Nativize turned off, which is what
+// TranslitWithOptions did with the zero value before NativizeMode was introduced.
+// Callers relying on that behaviour can wrap their options with it.
+func LegacyOptions(opts TranslitOptions) TranslitOptions {
+	if opts.Nativize == NativizeDefault {
+		opts.Nativize = NativizeOff
+	}
+	return opts
+}
+
 // TranslitWithContext converts text from one script to another with context support
 func TranslitWithContext(ctx context.Context, text string, from, to Script, opts TranslitOptions) (string, error) {
 	mgr, err := getOrCreateDefaultManager(ctx)
@@ -51,45 +131,63 @@ func TranslitWithOptions(text string, from, to Script, opts TranslitOptions) (st
 
 // Translit performs transliteration using a specific manager instance
 func (am *AksharamukhaManager) Translit(ctx context.Context, text string, from, to Script, opts TranslitOptions) (string, error) {
+	res, err := am.TranslitDetailed(ctx, Request{Text: text, From: from, To: to, Options: opts})
+	if err != nil {
+		return "", err
+	}
+	return res.Text, nil
+}
+
+// checkSource validates the input shared by every target of a conversion
+func checkSource(text string, from Script) error {
 	if text == "" {
-		return "", fmt.Errorf("empty text provided")
+		return fmt.Errorf("empty text provided")
 	}
 
 	// Validate scripts if provided
 	if from != "" && !IsValidScript(from) {
-		return "", fmt.Errorf("invalid source script: %s", from)
+		return fmt.Errorf("invalid source script: %s", from)
 	}
+	return nil
+}
+
+// checkTarget validates the target script and the options against the script pair
+func checkTarget(from, to Script, opts TranslitOptions) error {
 	if !IsValidScript(to) {
-		return "", fmt.Errorf("invalid target script: %s", to)
+		return fmt.Errorf("invalid target script: %s", to)
 	}
-
-	// Build the query URL
-	baseURL := am.GetBaseURL()
-	params := url.Values{}
-	
-	// Required parameters
-	params.Set("text", text)
-	params.Set("target", string(to))
-	
-	// Optional source script (if not provided, system will auto-detect)
-	if from != "" {
-		params.Set("source", string(from))
+	if err := validateOptions(from, to, opts); err != nil {
+		return err
 	}
-	
-	// Optional nativization parameter (only set if false, as true is default)
-	if !opts.Nativize {
-		params.Set("nativize", "false")
+	warnIndicSubset(from, to, opts)
+	return checkPair(from, to, opts)
+}
+
+// checkPair applies opts.PairPolicy, pairs with an unknown source or that the
+// capability matrix does not rate are let through
+func checkPair(from, to Script, opts TranslitOptions) error {
+	if from == "" || opts.PairPolicy == PairAllow {
+		return nil
 	}
-	
-	// Optional pre-options
-	if len(opts.PreOptions) > 0 {
-		params.Set("preoptions", strings.Join(opts.PreOptions, ","))
+	c := Supports(from, to)
+	if !c.LowQuality() {
+		return nil
 	}
-	
-	// Optional post-options
-	if len(opts.PostOptions) > 0 {
-		params.Set("postoptions", strings.Join(opts.PostOptions, ","))
+	if opts.PairPolicy == PairRefuse {
+		return fmt.Errorf("conversion from %s to %s is %s", from, to, c)
 	}
+	opts.warn(Warning{
+		Kind:    WarnLowQualityPair,
+		Message: fmt.Sprintf("conversion from %s to %s is %s", from, to, c),
+	})
+	return nil
+}
+
+// query sends a validated conversion request to the backend
+func (am *AksharamukhaManager) query(ctx context.Context, text string, from, to Script, opts TranslitOptions) (string, error) {
+	// Build the query URL
+	baseURL := am.GetBaseURL()
+	params := queryParams(text, from, to, opts)
 
 	client := &http.Client{}
 
@@ -99,8 +197,12 @@ func (am *AksharamukhaManager) Translit(ctx context.Context, text string, from,
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 	
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
+		if errors.Is(err, syscall.ECONNREFUSED) && !am.isInitialized() {
+			return "", fmt.Errorf("failed to make request, the backend is not running (was Init() called? see also WithAutoInit): %w", err)
+		}
 		return "", fmt.Errorf("failed to make request (THIS ERROR MAY BE CAUSED BY AN ACTIVE VPN, STOP IT AND RESTART %s): %w",
 			dockerutil.DockerBackendName(), err)
 	}
@@ -108,7 +210,11 @@ func (am *AksharamukhaManager) Translit(ctx context.Context, text string, from,
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		backendErr := &BackendError{StatusCode: resp.StatusCode}
+		if resp.StatusCode >= 500 {
+			backendErr.Traceback = am.backendTraceback(start, time.Now(), params.Encode())
+		}
+		return "", backendErr
 	}
 
 	// Read the response body
@@ -125,33 +231,79 @@ func (am *AksharamukhaManager) Translit(ctx context.Context, text string, from,
 		return "", fmt.Errorf("empty response received")
 	}
 
-	return result, nil
+	return am.rules.Apply(result, from, to, opts.Language, opts.TraceRules), nil
 }
 
-// RomanWithContext converts text from a given language to its romanized form with context support
-func RomanWithContext(ctx context.Context, text, languageCode string, opts TranslitOptions) (string, error) {
-	stdLang, ok := IsValidISO639(languageCode)
-	if !ok {
-		return "", fmt.Errorf("\"%s\" isn't a ISO-639 language code", languageCode)
+// queryParams builds the query string of an API request
+func queryParams(text string, from, to Script, opts TranslitOptions) url.Values {
+	params := url.Values{}
+	
+	// Required parameters
+	params.Set("text", text)
+	params.Set("target", string(to))
+	
+	// Optional source script (if not provided, system will auto-detect)
+	if from != "" {
+		params.Set("source", string(from))
 	}
-	sourceScript, err := DefaultScriptFor(stdLang)
-	if err != nil {
-		return "", err
+	
+	// Optional nativization parameter (omitted to keep the backend default)
+	switch opts.Nativize {
+	case NativizeOn:
+		params.Set("nativize", "true")
+	case NativizeOff:
+		params.Set("nativize", "false")
 	}
-
-	// Get the romanization scheme for the script
-	romanScheme, exists := Script2RomanScheme[string(sourceScript)]
-	if !exists {
-		return "", fmt.Errorf("no romanization scheme found for script %s", sourceScript)
+	
+	// Optional pre-options
+	if len(opts.PreOptions) > 0 {
+		params.Set("preoptions", strings.Join(opts.PreOptions, ","))
 	}
+	
+	// Optional post-options
+	if len(opts.PostOptions) > 0 {
+		params.Set("postoptions", strings.Join(opts.PostOptions, ","))
+	}
+
+	return params
+}
 
-	// Use the context-aware transliteration function
-	result, err := TranslitWithContext(ctx, text, sourceScript, Script(romanScheme), opts)
+// RomanWithContext converts text from a given language to its romanized form with context support.
+// The language is an ISO 639 code or a BCP 47 tag whose script subtag picks among the
+// language's scripts, e.g. "pa-Arab" for Punjabi in Shahmukhi.
+func RomanWithContext(ctx context.Context, text, languageCode string, opts TranslitOptions) (string, error) {
+	mgr, err := getOrCreateDefaultManager(ctx)
 	if err != nil {
-		return "", fmt.Errorf("romanization failed: %w", err)
+		return "", err
 	}
+	return roman(ctx, mgr, text, languageCode, opts)
+}
 
-	return result, nil
+// Roman romanizes text of the given language using this manager's profiles
+func (am *AksharamukhaManager) Roman(ctx context.Context, text, languageCode string, opts TranslitOptions) (string, error) {
+	return roman(ctx, am, text, languageCode, opts)
+}
+
+func roman(ctx context.Context, t Transliterator, text, languageCode string, opts TranslitOptions) (string, error) {
+	return romanStyled(ctx, t, text, languageCode, StyleAcademic, opts)
+}
+
+// romanScheme returns the academic romanization scheme of a source script, unless
+// the profile sets one. Scripts missing from Script2RomanScheme get the first
+// scheme of romanFallback the backend can produce for them.
+func romanScheme(source Script, profile RomanProfile) (Script, error) {
+	if profile.Scheme != "" {
+		return profile.Scheme, nil
+	}
+	if scheme, exists := Script2RomanScheme[string(source)]; exists {
+		return Script(scheme), nil
+	}
+	for _, scheme := range romanFallback {
+		if canRomanize(source, scheme) {
+			return scheme, nil
+		}
+	}
+	return "", fmt.Errorf("no romanization scheme found for script %s", source)
 }
 
 // Roman is the backward compatible version that uses a default context
@@ -164,11 +316,12 @@ func RomanWithOptions(text, languageCode string, opts TranslitOptions) (string,
 	return RomanWithContext(context.Background(), text, languageCode, opts)
 }
 
-// DefaultScriptFor gets the primary script of a given language
+// DefaultScriptFor gets the primary script of a given language, or for a BCP 47
+// tag with a script subtag such as "pa-Arab", the language's script it designates
 func DefaultScriptFor(languageCode string) (Script, error) {
-	stdLang, ok := IsValidISO639(languageCode)
-	if !ok {
-		return "", fmt.Errorf("\"%s\" isn't a ISO-639 language code", languageCode)
+	stdLang, subtag, err := parseLangTag(languageCode)
+	if err != nil {
+		return "", err
 	}
 
 	// Get the script for the language
@@ -180,10 +333,47 @@ func DefaultScriptFor(languageCode string) (Script, error) {
 		return "", fmt.Errorf("empty script list for language code %s", stdLang)
 	}
 
+	if subtag != "" {
+		for _, s := range scripts {
+			if strings.EqualFold(Script(s).ISO15924(), subtag) {
+				return Script(s), nil
+			}
+		}
+		return "", fmt.Errorf("%s cannot be written in the %s script (its scripts are %s)",
+			stdLang, subtag, strings.Join(scripts, ", "))
+	}
+
 	// Get the primary script (first in the list)
 	return Script(scripts[0]), nil
 }
 
+// parseLangTag accepts an ISO 639 code or a BCP 47 tag and returns the ISO 639-3
+// code of the language along with the script subtag, if any. Other subtags such
+// as the region are ignored.
+func parseLangTag(languageCode string) (stdLang, scriptSubtag string, err error) {
+	if !strings.ContainsAny(languageCode, "-_") {
+		stdLang, ok := IsValidISO639(languageCode)
+		if !ok {
+			return "", "", fmt.Errorf("\"%s\" isn't a ISO-639 language code", languageCode)
+		}
+		return stdLang, "", nil
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(languageCode, "_", "-"))
+	if err != nil {
+		return "", "", fmt.Errorf("\"%s\" isn't a BCP 47 language tag: %w", languageCode, err)
+	}
+	base, _ := tag.Base()
+	stdLang, ok := IsValidISO639(base.ISO3())
+	if !ok {
+		return "", "", fmt.Errorf("\"%s\" isn't a ISO-639 language code", base)
+	}
+	if script, conf := tag.Script(); conf == language.Exact {
+		scriptSubtag = script.String()
+	}
+	return stdLang, scriptSubtag, nil
+}
+
 func IsValidISO639(lang string) (stdLang string, ok bool) {
 	code := iso.FromAnyCode(lang)
 	if code == nil {
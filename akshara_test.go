@@ -2,9 +2,18 @@ package aksharamukha
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"slices"
 	"testing"
 	"time"
 	"strings"
+	"sync/atomic"
 )
 
 func TestRomanizationBackwardCompatible(t *testing.T) {
@@ -339,4 +348,1211 @@ func TestInvalidInputs(t *testing.T) {
 	if err == nil || !strings.Contains(err.Error(), "context canceled") {
 		t.Errorf("Expected context cancellation error, got: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestExtractTraceback(t *testing.T) {
+	lines := []LogLine{
+		{Stream: "stdout", Text: "GET /api/public?text=foo"},
+		{Stream: "stderr", Text: "Traceback (most recent call last):"},
+		{Stream: "stderr", Text: `  File "/aksharamukha/transliterate.py", line 42, in convert`},
+		{Stream: "stderr", Text: "KeyError: 'x'"},
+	}
+	tb := extractTraceback(lines, "text=foo")
+	if len(tb) != 3 || tb[0] != "Traceback (most recent call last):" || tb[2] != "KeyError: 'x'" {
+		t.Errorf("extractTraceback() = %q", tb)
+	}
+
+	// Without a traceback, only stderr lines are kept
+	tb = extractTraceback(lines[:1], "text=foo")
+	if len(tb) != 0 {
+		t.Errorf("extractTraceback() = %q, want none", tb)
+	}
+
+	// With concurrent failures, the traceback logged after the request's own line is picked
+	concurrent := []LogLine{
+		{Stream: "stderr", Text: "[ERROR] Error handling request /api/public?target=ISO&text=foo"},
+		{Stream: "stderr", Text: "Traceback (most recent call last):"},
+		{Stream: "stderr", Text: "KeyError: 'foo'"},
+		{Stream: "stderr", Text: "[ERROR] Error handling request /api/public?target=ISO&text=bar"},
+		{Stream: "stderr", Text: "Traceback (most recent call last):"},
+		{Stream: "stderr", Text: "KeyError: 'bar'"},
+	}
+	tb = extractTraceback(concurrent, "target=ISO&text=foo")
+	if len(tb) != 2 || tb[1] != "KeyError: 'foo'" {
+		t.Errorf("extractTraceback(foo) = %q", tb)
+	}
+	tb = extractTraceback(concurrent, "target=ISO&text=bar")
+	if len(tb) != 2 || tb[1] != "KeyError: 'bar'" {
+		t.Errorf("extractTraceback(bar) = %q", tb)
+	}
+	if tb = extractTraceback(concurrent, "text=baz"); tb != nil {
+		t.Errorf("extractTraceback(unmatched) = %q, want none", tb)
+	}
+}
+
+func TestClosedManagerRejectsRequests(t *testing.T) {
+	am := &AksharamukhaManager{}
+	if err := am.begin(); err != nil {
+		t.Fatalf("begin() on open manager = %v", err)
+	}
+	am.end()
+
+	am.markClosed()
+	_, err := am.Translit(context.Background(), "नमस्ते", Devanagari, Tamil, DefaultOptions())
+	if err != ErrClosed {
+		t.Errorf("Translit() on closed manager = %v, want ErrClosed", err)
+	}
+}
+
+type fakeTransliterator struct {
+	Transliterator
+	closed bool
+	// arguments of the last Translit call
+	from, to Script
+	opts     TranslitOptions
+	// every Translit call
+	calls []Request
+}
+
+func (f *fakeTransliterator) Translit(ctx context.Context, text string, from, to Script, opts TranslitOptions) (string, error) {
+	f.from, f.to, f.opts = from, to, opts
+	f.calls = append(f.calls, Request{Text: text, From: from, To: to, Options: opts})
+	return "fake:" + text, nil
+}
+
+func (f *fakeTransliterator) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeTransliterator) Shutdown(ctx context.Context) error {
+	return f.Close()
+}
+
+func TestInitWithCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Nothing is created, so there is nothing to tear down either
+	am := &AksharamukhaManager{}
+	if err := am.Init(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Init() with cancelled ctx = %v, want context.Canceled", err)
+	}
+	if err := am.InitRecreate(ctx, true); !errors.Is(err, context.Canceled) {
+		t.Errorf("InitRecreate() with cancelled ctx = %v, want context.Canceled", err)
+	}
+	if am.isInitialized() {
+		t.Error("manager marked initialized after a cancelled Init")
+	}
+}
+
+func TestDetachOnSuccess(t *testing.T) {
+	// Cancelling after a successful operation does not reach the attached containers
+	ctx, cancel := context.WithCancel(context.Background())
+	var opCtx context.Context
+	if err := detachOnSuccess(ctx, func(c context.Context) error { opCtx = c; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	if err := opCtx.Err(); err != nil {
+		t.Errorf("operation context after success and cancel = %v, want live", err)
+	}
+
+	// Cancelling while it runs does
+	ctx, cancel = context.WithCancel(context.Background())
+	err := detachOnSuccess(ctx, func(c context.Context) error {
+		cancel()
+		<-c.Done()
+		return c.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("detachOnSuccess() cancelled midway = %v, want context.Canceled", err)
+	}
+}
+
+func TestEnsureInit(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	failing := true
+	am := &AksharamukhaManager{autoInit: true}
+	am.initFunc = func(context.Context) error {
+		calls.Add(1)
+		<-release
+		if failing {
+			return errors.New("docker unavailable")
+		}
+		am.initMu.Lock()
+		am.initialized = true
+		am.initMu.Unlock()
+		return nil
+	}
+
+	// Concurrent first callers share a single startup, and all see its failure
+	const callers = 8
+	errs := make(chan error, callers)
+	for range callers {
+		go func() { errs <- am.ensureInit(context.Background()) }()
+	}
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// let the other callers reach the startup in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	for range callers {
+		if err := <-errs; err == nil || !strings.Contains(err.Error(), "docker unavailable") {
+			t.Errorf("ensureInit() = %v, want the startup failure", err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("Init ran %d times for concurrent callers, want 1", n)
+	}
+
+	// A failed startup is retried by the next call, then never run again
+	failing = false
+	if err := am.ensureInit(context.Background()); err != nil {
+		t.Fatalf("ensureInit() after failure = %v", err)
+	}
+	if err := am.ensureInit(context.Background()); err != nil {
+		t.Fatalf("ensureInit() once initialized = %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Init ran %d times, want 2 (failure then retry)", n)
+	}
+}
+
+func TestPackageCallsAfterShutdown(t *testing.T) {
+	if err := SetDefault(&fakeTransliterator{}); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	defer ResetDefault()
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if _, err := Translit("abc", IAST, Devanagari); !errors.Is(err, ErrClosed) {
+		t.Errorf("Translit() after Shutdown() = %v, want ErrClosed", err)
+	}
+	if _, err := Default(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Default() after Shutdown() = %v, want ErrClosed", err)
+	}
+
+	// Installing a new default reopens the package-level functions
+	fake := &fakeTransliterator{}
+	if err := SetDefault(fake); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	if result, err := Translit("abc", IAST, Devanagari); err != nil || result != "fake:abc" {
+		t.Errorf("Translit() after SetDefault() = %q, %v", result, err)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	fake := &fakeTransliterator{}
+	if err := SetDefault(fake); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	defer ResetDefault()
+
+	if d, err := Default(); err != nil || d != fake {
+		t.Errorf("Default() = %v, %v, want the installed instance", d, err)
+	}
+	result, err := Translit("abc", IAST, Devanagari)
+	if err != nil || result != "fake:abc" {
+		t.Errorf("Translit() = %q, %v, want routed to the installed instance", result, err)
+	}
+
+	// Instances installed by the caller are not closed by the package
+	if err := ResetDefault(); err != nil {
+		t.Fatalf("ResetDefault() error = %v", err)
+	}
+	if fake.closed {
+		t.Error("ResetDefault() closed a caller-owned instance")
+	}
+	if err := StopWithContext(context.Background()); err == nil {
+		t.Error("StopWithContext() after ResetDefault() should fail")
+	}
+
+	// Nor by the package-level Close and Shutdown
+	if err := SetDefault(fake); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	if err := Close(); err != nil || fake.closed {
+		t.Errorf("Close() = %v, closed caller-owned instance: %v", err, fake.closed)
+	}
+	if err := SetDefault(fake); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	if err := Shutdown(context.Background()); err != nil || fake.closed {
+		t.Errorf("Shutdown() = %v, closed caller-owned instance: %v", err, fake.closed)
+	}
+}
+
+func TestOptionsFor(t *testing.T) {
+	pre, post := OptionsFor(Devanagari, ISO)
+	if !slices.Contains(pre, PreRemoveSchwaHindi) {
+		t.Errorf("OptionsFor(Devanagari, ISO) pre = %v, want RemoveSchwaHindi", pre)
+	}
+	if slices.Contains(pre, PreThaiPhonetic) {
+		t.Errorf("OptionsFor(Devanagari, ISO) pre = %v, should not contain ThaiPhonetic", pre)
+	}
+	if !slices.Contains(post, PostRemoveDiacritics) || slices.Contains(post, PostThaiPhonetic) {
+		t.Errorf("OptionsFor(Devanagari, ISO) post = %v", post)
+	}
+
+	for _, name := range PostOptions() {
+		if info, _ := name.Info(); info.Name != string(name) || info.Description == "" {
+			t.Errorf("post-option %s has incomplete catalog entry %+v", name, info)
+		}
+	}
+}
+
+func TestValidateOptions(t *testing.T) {
+	opts := DefaultOptions().WithPreOptions(PreRemoveSchwaHindi)
+	if err := ValidateOptions(Devanagari, ISO, opts); err != nil {
+		t.Errorf("ValidateOptions() = %v, want nil", err)
+	}
+
+	// Misspelled and inapplicable options
+	opts = TranslitOptions{PreOptions: []string{"RemoveSchwaHindee"}, PostOptions: []string{string(PostThaiPhonetic)}}
+	err := ValidateOptions(Devanagari, ISO, opts)
+	if err == nil || !strings.Contains(err.Error(), `unknown pre-option "RemoveSchwaHindee"`) ||
+		!strings.Contains(err.Error(), `post-option "ThaiPhonetic" does not apply`) {
+		t.Errorf("ValidateOptions() = %v", err)
+	}
+
+	// Conversions reject them, unless lenient which reports warnings instead
+	var warnings []Warning
+	opts.OnWarning = func(w Warning) { warnings = append(warnings, w) }
+	if err := validateOptions(Devanagari, ISO, opts); err == nil {
+		t.Error("validateOptions() should reject the options by default")
+	}
+	opts.Lenient = true
+	if err := validateOptions(Devanagari, ISO, opts); err != nil {
+		t.Errorf("validateOptions() lenient = %v, want nil", err)
+	}
+	if len(warnings) != 2 || warnings[0].Kind != WarnUnknownOption || warnings[1].Kind != WarnInapplicableOption {
+		t.Errorf("validateOptions() lenient warnings = %v", warnings)
+	}
+}
+
+func TestPresets(t *testing.T) {
+	opts, err := DefaultOptions().WithPreset("hindi-schwa-deletion")
+	if err != nil {
+		t.Fatalf("WithPreset() error = %v", err)
+	}
+	if !slices.Equal(opts.PreOptions, []string{"RemoveSchwaHindi"}) {
+		t.Errorf("WithPreset() pre-options = %v", opts.PreOptions)
+	}
+
+	if _, err := DefaultOptions().WithPreset("no-such-preset"); err == nil {
+		t.Error("WithPreset() with unknown name should fail")
+	}
+
+	custom := Preset{Name: "test-custom", PostOptions: []PostOption{PostTamilRemoveNumbers}}
+	if err := RegisterPreset(custom); err != nil {
+		t.Fatalf("RegisterPreset() error = %v", err)
+	}
+	opts, _ = opts.WithPreset("test-custom")
+	opts, _ = opts.WithPreset("test-custom")
+	if !slices.Equal(opts.PostOptions, []string{"TamilRemoveNumbers"}) {
+		t.Errorf("applying a preset twice should not duplicate options, got %v", opts.PostOptions)
+	}
+}
+
+func TestNativizeParam(t *testing.T) {
+	tests := []struct {
+		opts TranslitOptions
+		want string
+		set  bool
+	}{
+		{DefaultOptions(), "", false},
+		{TranslitOptions{Nativize: NativizeOn}, "true", true},
+		{TranslitOptions{Nativize: NativizeOff}, "false", true},
+		{LegacyOptions(DefaultOptions()), "false", true},
+		{TranslitOptions{Nativize: NativizeFromBool(true)}, "", false},
+	}
+	for _, tt := range tests {
+		params := queryParams("text", Devanagari, ISO, tt.opts)
+		if params.Has("nativize") != tt.set || params.Get("nativize") != tt.want {
+			t.Errorf("Nativize %v: nativize param = %q (set: %v), want %q (set: %v)",
+				tt.opts.Nativize, params.Get("nativize"), params.Has("nativize"), tt.want, tt.set)
+		}
+	}
+}
+
+func TestRomanProfiles(t *testing.T) {
+	fake := &fakeTransliterator{}
+	ctx := context.Background()
+
+	if _, err := roman(ctx, fake, "नमस्ते", "hin", DefaultOptions()); err != nil {
+		t.Fatalf("roman() error = %v", err)
+	}
+	if fake.to != ISO || !slices.Contains(fake.opts.PreOptions, "RemoveSchwaHindi") {
+		t.Errorf("Hindi romanized to %s with %v, want ISO with schwa deletion", fake.to, fake.opts.PreOptions)
+	}
+
+	if _, err := roman(ctx, fake, "संस्कृतम्", "san", DefaultOptions()); err != nil {
+		t.Fatalf("roman() error = %v", err)
+	}
+	if len(fake.opts.PreOptions) != 0 {
+		t.Errorf("Sanskrit romanized with %v, want no schwa deletion", fake.opts.PreOptions)
+	}
+
+	// Global override
+	if err := SetRomanProfile("san", RomanProfile{Scheme: IAST, PostProcess: strings.ToUpper}); err != nil {
+		t.Fatalf("SetRomanProfile() error = %v", err)
+	}
+	defer SetRomanProfile("san", RomanProfile{Scheme: ISO})
+	result, _ := roman(ctx, fake, "संस्कृतम्", "sa", DefaultOptions())
+	if fake.to != IAST || result != strings.ToUpper("fake:संस्कृतम्") {
+		t.Errorf("roman() with global profile = %q to %s", result, fake.to)
+	}
+
+	// Per manager override takes precedence
+	am := &AksharamukhaManager{}
+	WithRomanProfile("san", RomanProfile{Scheme: HK})(am)
+	if p, _ := profileFor(am, "san"); p.Scheme != HK {
+		t.Errorf("manager profile = %+v, want HK scheme", p)
+	}
+}
+
+func TestRomanStyled(t *testing.T) {
+	fake := &fakeTransliterator{}
+	ctx := context.Background()
+
+	tests := []struct {
+		lang     string
+		style    RomanStyle
+		to       Script
+		post     []string
+		fallback bool
+	}{
+		{"hin", StyleAcademic, ISO, nil, false},
+		{"hin", StyleReadable, RomanReadable, nil, false},
+		{"hin", StyleASCII, RomanReadable, []string{"RemoveDiacritics"}, false},
+		{"ben", StyleIPA, IPA, nil, false},
+		{"ara", StyleIPA, ISO233, nil, true},
+		{"ara", StyleASCII, ISO233, []string{"RemoveDiacritics"}, true},
+	}
+	for _, tt := range tests {
+		var warnings []Warning
+		opts := TranslitOptions{OnWarning: func(w Warning) { warnings = append(warnings, w) }}
+		if _, err := romanStyled(ctx, fake, "text", tt.lang, tt.style, opts); err != nil {
+			t.Errorf("romanStyled(%s, %s) error = %v", tt.lang, tt.style, err)
+			continue
+		}
+		if fake.to != tt.to || !slices.Equal(fake.opts.PostOptions, tt.post) {
+			t.Errorf("romanStyled(%s, %s) → %s %v, want %s %v", tt.lang, tt.style, fake.to, fake.opts.PostOptions, tt.to, tt.post)
+		}
+		if fell := len(warnings) > 0 && warnings[0].Kind == WarnStyleFallback; fell != tt.fallback {
+			t.Errorf("romanStyled(%s, %s) warnings = %v, want fallback: %v", tt.lang, tt.style, warnings, tt.fallback)
+		}
+	}
+}
+
+// newTestManager returns a manager talking to a fake backend that answers with handler
+func newTestManager(t *testing.T, handler http.HandlerFunc) *AksharamukhaManager {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &AksharamukhaManager{baseURL: srv.URL, initialized: true}
+}
+
+func TestTranslitMulti(t *testing.T) {
+	am := newTestManager(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("target") == "Tamil" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(r.URL.Query().Get("target") + ":" + r.URL.Query().Get("text")))
+	})
+
+	results, err := am.TranslitMulti(context.Background(), "नमस्ते", Devanagari,
+		[]Script{Telugu, Tamil, ISO, Telugu, "Klingon"}, DefaultOptions())
+
+	var multi MultiError
+	if !errors.As(err, &multi) || len(multi) != 2 || multi[Tamil] == nil || multi["Klingon"] == nil {
+		t.Fatalf("TranslitMulti() error = %v, want failures for Tamil and Klingon", err)
+	}
+	var backendErr *BackendError
+	if !errors.As(multi[Tamil], &backendErr) || backendErr.StatusCode != 500 {
+		t.Errorf("Tamil error = %v, want a BackendError", multi[Tamil])
+	}
+	if len(results) != 2 || results[Telugu] != "Telugu:नमस्ते" || results[ISO] != "ISO:नमस्ते" {
+		t.Errorf("TranslitMulti() results = %v", results)
+	}
+
+	// Warnings of concurrent targets reach OnWarning one at a time
+	var warnings []Warning
+	opts := DefaultOptions()
+	opts.PostOptions = []string{"NoSuchOption"}
+	opts.Lenient = true
+	opts.OnWarning = func(w Warning) { warnings = append(warnings, w) }
+	targets := []Script{Telugu, ISO, Kannada, Malayalam, Gujarati, Oriya}
+	if _, err := am.TranslitMulti(context.Background(), "नमस्ते", Devanagari, targets, opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != len(targets) {
+		t.Errorf("TranslitMulti() warnings = %v, want one per target", warnings)
+	}
+}
+
+func TestFoldASCII(t *testing.T) {
+	tests := []struct {
+		in    string
+		rules FoldRules
+		want  string
+	}{
+		{"namastē", DefaultFoldRules, "namaste"},
+		{"saṁskr̥tam", DefaultFoldRules, "samskritam"},
+		{"Ṣaṭkoṇa", DefaultFoldRules, "Shatkona"},
+		{"ṣaṣṭha", FoldRules{Replacements: map[string]string{"ṣ": "s"}}, "sastha"},
+		{"rāmāyaṇa", FoldRules{LongVowels: LongVowelDouble}, "raamaayana"},
+		{"ʿarabīy", DefaultFoldRules, "arabiy"},
+	}
+	for _, tt := range tests {
+		if got := FoldASCII(tt.in, tt.rules); got != tt.want {
+			t.Errorf("FoldASCII(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSlug(t *testing.T) {
+	if got := Slug("  Śrī Rāmāyaṇa: Bālakāṇḍa! ", DefaultFoldRules); got != "shri-ramayana-balakanda" {
+		t.Errorf("Slug() = %q", got)
+	}
+
+	set := NewSlugSet(DefaultFoldRules)
+	got := []string{set.Unique("namastē"), set.Unique("Namaste"), set.Unique("namaste-2"), set.Unique("॥")}
+	want := []string{"namaste", "namaste-2", "namaste-2-2", "n-a"}
+	if !slices.Equal(got, want) {
+		t.Errorf("SlugSet.Unique() = %v, want %v", got, want)
+	}
+}
+
+func TestRules(t *testing.T) {
+	rs, err := LoadRulesFS(os.DirFS("testdata"), "rules*.json")
+	if err != nil {
+		t.Fatalf("LoadRulesFS() error = %v", err)
+	}
+
+	am := newTestManager(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("saṃsāra kamala"))
+	})
+	am.rules = rs
+
+	var fired []string
+	opts := TranslitOptions{Language: "hin", TraceRules: func(tr RuleTrace) { fired = append(fired, tr.Rule) }}
+	result, err := am.Translit(context.Background(), "संसार कमल", Devanagari, ISO, opts)
+	if err != nil {
+		t.Fatalf("Translit() error = %v", err)
+	}
+	if result != "saṁsār kamal" {
+		t.Errorf("Translit() with rules = %q", result)
+	}
+	if !slices.Equal(fired, []string{"anusvara-dot-above", "hindi-final-a"}) {
+		t.Errorf("rules fired = %v", fired)
+	}
+
+	if _, err := NewRuleSet(Rule{Name: "bad", Regex: true, Match: "("}); err == nil {
+		t.Error("NewRuleSet() with an invalid regex should fail")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	calls := 0
+	am := newTestManager(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte("backend:" + r.URL.Query().Get("text")))
+	})
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, req Request) (string, error) {
+				order = append(order, name)
+				return next(ctx, req)
+			}
+		}
+	}
+	glossary := func(next Handler) Handler {
+		return func(ctx context.Context, req Request) (string, error) {
+			if req.Text == "ॐ" {
+				return "Om", nil
+			}
+			result, err := next(ctx, req)
+			return strings.ToUpper(result), err
+		}
+	}
+	WithMiddleware(trace("outer"), glossary, trace("inner"))(am)
+
+	result, err := am.Translit(context.Background(), "ॐ", Devanagari, ISO, DefaultOptions())
+	if err != nil || result != "Om" || calls != 0 {
+		t.Errorf("short-circuited Translit() = %q, %v with %d backend calls", result, err, calls)
+	}
+	if !slices.Equal(order, []string{"outer"}) {
+		t.Errorf("middleware order = %v, want [outer]", order)
+	}
+
+	order = nil
+	results, err := am.TranslitMulti(context.Background(), "x", Devanagari, []Script{ISO}, DefaultOptions())
+	if err != nil || results[ISO] != "BACKEND:X" {
+		t.Errorf("TranslitMulti() through middleware = %v, %v", results, err)
+	}
+	if !slices.Equal(order, []string{"outer", "inner"}) {
+		t.Errorf("middleware order = %v, want [outer inner]", order)
+	}
+}
+
+func TestScriptRegistry(t *testing.T) {
+	if got := len(Scripts()); got != 141 {
+		t.Errorf("len(Scripts()) = %d, want 141", got)
+	}
+	for _, s := range Scripts() {
+		info, ok := s.Info()
+		if !ok || info.Script != s {
+			t.Errorf("%s.Info() = %+v, %v", s, info, ok)
+		}
+		if info.Category == 0 {
+			t.Errorf("%s has no category", s)
+		}
+		if info.ISO15924 != "" && len(info.ISO15924) != 4 {
+			t.Errorf("%s has ISO 15924 code %q", s, info.ISO15924)
+		}
+		for _, r := range info.Ranges {
+			if r.Lo > r.Hi {
+				t.Errorf("%s has inverted range %X-%X", s, r.Lo, r.Hi)
+			}
+		}
+	}
+
+	if !IsValidScript(Devanagari) || IsValidScript("Klingon") {
+		t.Error("IsValidScript() disagrees with the registry")
+	}
+	if !IAST.IsRoman() || Devanagari.IsRoman() {
+		t.Error("IsRoman() misclassifies IAST or Devanagari")
+	}
+	if Urdu.Direction() != RightToLeft || Mongolian.Direction() != TopToBottom || Tamil.Direction() != LeftToRight {
+		t.Error("Direction() returned an unexpected value")
+	}
+	if !Brahmi.Category().Has(CategoryBrahmic|CategoryHistoric) || Devanagari.Category().Has(CategoryHistoric) {
+		t.Errorf("Brahmi category = %s, Devanagari category = %s", Brahmi.Category(), Devanagari.Category())
+	}
+	if Devanagari.ISO15924() != "Deva" || Ariyaka.ISO15924() != "" {
+		t.Error("ISO15924() returned an unexpected code")
+	}
+	if info, _ := Thai.Info(); info.Support != SupportIndicSubset {
+		t.Errorf("Thai support = %s, want indic-subset", info.Support)
+	}
+}
+
+func TestIndicSubsetWarning(t *testing.T) {
+	am := newTestManager(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	var warnings []Warning
+	opts := DefaultOptions()
+	opts.OnWarning = func(w Warning) { warnings = append(warnings, w) }
+
+	if _, err := am.Translit(context.Background(), "धम्म", Devanagari, Thai, opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || warnings[0].Kind != WarnIndicSubset || !strings.Contains(warnings[0].Message, "Thai") {
+		t.Errorf("Devanagari → Thai warnings = %v", warnings)
+	}
+
+	warnings = nil
+	if _, err := am.Translit(context.Background(), "धम्म", Devanagari, ISO, opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Devanagari → ISO warnings = %v, want none", warnings)
+	}
+
+	opts.SuppressWarnings = []WarningKind{WarnIndicSubset}
+	if _, err := am.Translit(context.Background(), "ธมฺม", Thai, Tibetan, opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("suppressed warnings = %v, want none", warnings)
+	}
+}
+
+func TestTranslitDetailed(t *testing.T) {
+	var sources []string
+	am := newTestManager(t, func(w http.ResponseWriter, r *http.Request) {
+		sources = append(sources, r.URL.Query().Get("source"))
+		w.Write([]byte("dhamma"))
+	})
+
+	var forwarded []Warning
+	opts := DefaultOptions()
+	opts.OnWarning = func(w Warning) { forwarded = append(forwarded, w) }
+	opts.SuppressWarnings = []WarningKind{WarnIndicSubset}
+
+	res, err := am.TranslitDetailed(context.Background(), Request{Text: "धम्म", To: Thai, Options: opts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "dhamma" || res.Source != Devanagari || res.Target != Thai || res.Cached {
+		t.Errorf("TranslitDetailed() = %+v", res)
+	}
+	if len(res.Warnings) != 1 || res.Warnings[0].Kind != WarnIndicSubset || len(forwarded) != 0 {
+		t.Errorf("warnings = %v, forwarded = %v", res.Warnings, forwarded)
+	}
+	if res.Duration <= 0 {
+		t.Errorf("Duration = %v", res.Duration)
+	}
+
+	// The detected source is only reported, unless the caller asks to send it
+	opts.SendDetectedSource = true
+	if _, err := am.TranslitDetailed(context.Background(), Request{Text: "धम्म", To: Thai, Options: opts}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(sources, []string{"", "Devanagari"}) {
+		t.Errorf("backend received sources %q, want none then the detected one", sources)
+	}
+
+	cache := func(next Handler) Handler {
+		return func(ctx context.Context, req Request) (string, error) {
+			MarkCached(ctx)
+			return "cached", nil
+		}
+	}
+	WithMiddleware(cache)(am)
+	res, err = am.TranslitDetailed(context.Background(), Request{Text: "धम्म", From: Devanagari, To: ISO})
+	if err != nil || res.Text != "cached" || !res.Cached {
+		t.Errorf("cached TranslitDetailed() = %+v, %v", res, err)
+	}
+
+	// The version is reported as looked up, and a failed lookup is not retried
+	am.backendVersion, am.versionResolved = "2.3", true
+	res, err = am.TranslitDetailed(context.Background(), Request{Text: "धम्म", From: Devanagari, To: ISO})
+	if err != nil || res.BackendVersion != "2.3" {
+		t.Errorf("TranslitDetailed() version = %q, %v", res.BackendVersion, err)
+	}
+	am.backendVersion, am.versionResolved = "", false
+	if v := am.BackendVersion(context.Background()); v != "" || !am.versionResolved {
+		t.Errorf("BackendVersion() without a container = %q, resolved %v", v, am.versionResolved)
+	}
+}
+
+func TestDetectScript(t *testing.T) {
+	top := func(text string) Script {
+		guesses := DetectScript(text)
+		if len(guesses) == 0 {
+			return ""
+		}
+		return guesses[0].Script
+	}
+	for text, want := range map[string]Script{
+		"धर्मक्षेत्रे कुरुक्षेत्रे": Devanagari,
+		"مدينة كبيرة":    Arab,
+		"پدر و مادر":     ArabFa,
+		"یہ میرا گھر ہے": Urdu,
+		"ਪੰਜਾਬੀ ਬੋਲੀ":    Gurmukhi,
+		"ساڈا پنجاݨ":     Shahmukhi,
+		"ひらがなーです":        Hiragana,
+		"カタカナー":          Katakana,
+		"ᨲᩫ᩠ᩅᨾᩮᩥᨦ":       TaiTham,
+		"অসমীয়া ৰাজ্য":  Assamese,
+		"dharma":         Latn,
+	} {
+		if got := top(text); got != want {
+			t.Errorf("DetectScript(%q) top = %s, want %s (%v)", text, got, want, DetectScript(text))
+		}
+	}
+
+	guesses := DetectScript("ᨲᩫ᩠ᩅᨾᩮᩥᨦ")
+	if len(guesses) != 4 {
+		t.Errorf("Tai Tham guesses = %v, want the 4 variants", guesses)
+	}
+
+	guesses = DetectScript("नमस्ते world 2024!")
+	if len(guesses) != 2 || guesses[0].Script != Devanagari || guesses[1].Script != Latn {
+		t.Fatalf("mixed text guesses = %v", guesses)
+	}
+	if sum := guesses[0].Confidence + guesses[1].Confidence; sum < 0.99 || sum > 1.01 {
+		t.Errorf("confidences sum to %f, want 1", sum)
+	}
+	if DetectScript("123 !?") != nil {
+		t.Error("DetectScript() of text without letters should be nil")
+	}
+
+	// Blocks shared with variants resolve to their primary script, or stay undecided
+	for text, want := range map[string]Script{
+		"ธรรมะ":  Thai,
+		"মানুষ":  Bengali,
+		"ທຳມະ":   Lao,
+		"שלום":   Hebrew,
+		"မင်္ဂလာ": Burmese,
+		"தமிழ்":  Tamil,
+		"ᯅᯖᯂ᯲":   "",
+		"ܫܠܡܐ":   "",
+	} {
+		if got := detectSource(text); got != want {
+			t.Errorf("detectSource(%q) = %q, want %q (%v)", text, got, want, DetectScript(text))
+		}
+	}
+}
+
+func TestSegmentByScript(t *testing.T) {
+	text := "नमस्ते दुनिया, see https://example.com 👋 2024 क्षत्रिय।"
+	segments := SegmentByScript(text)
+
+	var joined strings.Builder
+	for _, seg := range segments {
+		if text[seg.Offset:seg.Offset+len(seg.Text)] != seg.Text {
+			t.Errorf("segment %q has wrong offset %d", seg.Text, seg.Offset)
+		}
+		joined.WriteString(seg.Text)
+	}
+	if joined.String() != text {
+		t.Fatalf("segments do not reassemble the text: %q", joined.String())
+	}
+	if segments[0].Text != "नमस्ते दुनिया" || segments[0].Script != Devanagari {
+		t.Errorf("first segment = %+v", segments[0])
+	}
+	last := segments[len(segments)-1]
+	if last.Text != "क्षत्रिय।" || last.Script != Devanagari {
+		t.Errorf("last segment = %+v", last)
+	}
+
+	if segs := SegmentByScript("یہ گھر ہے"); len(segs) != 1 || segs[0].Script != Urdu {
+		t.Errorf("Urdu segments = %+v", segs)
+	}
+}
+
+func TestTranslitSpans(t *testing.T) {
+	var sent []string
+	am := newTestManager(t, func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.URL.Query().Get("text"))
+		w.Write([]byte("<" + r.URL.Query().Get("text") + ">"))
+	})
+
+	text := "ॐ नमः, visit https://example.com 👋 ॐ नमः"
+	result, err := am.TranslitSpans(context.Background(), text, Devanagari, ISO, DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<ॐ नमः>, visit https://example.com 👋 <ॐ नमः>"; result != want {
+		t.Errorf("TranslitSpans() = %q, want %q", result, want)
+	}
+	if len(sent) != 1 {
+		t.Errorf("backend received %q, want a single request for the repeated span", sent)
+	}
+
+	if _, err := am.TranslitSpans(context.Background(), "hello", "", ISO, DefaultOptions()); err == nil {
+		t.Error("TranslitSpans() without a detectable source should fail")
+	}
+
+	// Romanization schemes are read from the Latin runs, other scripts are kept
+	sent = nil
+	result, err = am.TranslitSpans(context.Background(), "dharmakṣetre — धर्म 42", IAST, Devanagari, DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<dharmakṣetre> — धर्म 42"; result != want {
+		t.Errorf("TranslitSpans(IAST) = %q, want %q", result, want)
+	}
+	if len(sent) != 1 || sent[0] != "dharmakṣetre" {
+		t.Errorf("backend received %q, want the Latin run only", sent)
+	}
+
+	// SkipSpan keeps English words of an IAST text
+	english := map[string]bool{"the": true, "field": true, "of": true}
+	opts := DefaultOptions()
+	opts.SkipSpan = func(seg Segment) bool { return english[seg.Text] }
+	result, err = am.TranslitSpans(context.Background(), "dharmakṣetre, the field of dharma", IAST, Devanagari, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<dharmakṣetre>, the field of <dharma>"; result != want {
+		t.Errorf("TranslitSpans(IAST, SkipSpan) = %q, want %q", result, want)
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	for in, want := range map[string]Script{
+		"devanagari":   Devanagari,
+		"Deva":         Devanagari,
+		"hindi-script": Devanagari,
+		"Latin":        Latn,
+		"latn":         Latn,
+		"iast":         IAST,
+		"hebr-ar":      HebrAr,
+		"ARAB_FA":      ArabFa,
+		"Beng":         Bengali,
+		"Thai":         Thai,
+		" tamil ":      Tamil,
+	} {
+		if got, err := ParseScript(in); err != nil || got != want {
+			t.Errorf("ParseScript(%q) = %s, %v, want %s", in, got, err, want)
+		}
+	}
+
+	_, err := ParseScript("devangari")
+	if err == nil || !strings.Contains(err.Error(), "Devanagari") {
+		t.Errorf("ParseScript(typo) error = %v, want a suggestion", err)
+	}
+	if _, err := ParseScript("klingon-pIqaD-xyz"); err == nil {
+		t.Error("ParseScript(garbage) should fail")
+	}
+
+	var s Script
+	if err := s.UnmarshalText([]byte("sinhalese")); err != nil || s != Sinhala {
+		t.Errorf("UnmarshalText() = %s, %v", s, err)
+	}
+	if text, _ := HebrAr.MarshalText(); string(text) != "Hebr-Ar" {
+		t.Errorf("MarshalText() = %s", text)
+	}
+
+	rs, err := LoadRules(strings.NewReader(`[{"name": "r", "from": "deva", "to": "iso", "match": "x", "replace": "y"}]`))
+	if err != nil || rs.rules[0].From != Devanagari || rs.rules[0].To != ISO {
+		t.Errorf("rules with lowercase scripts = %+v, %v", rs, err)
+	}
+}
+
+func TestRomanLanguageTags(t *testing.T) {
+	fake := &fakeTransliterator{}
+	ctx := context.Background()
+
+	for tag, want := range map[string]Script{
+		"pa":       Gurmukhi,
+		"pa-Arab":  Shahmukhi,
+		"pa-Guru":  Gurmukhi,
+		"ur-Arab":  Urdu,
+		"sa-Sidd":  Siddham,
+		"ja-Kana":  Katakana,
+		"kas-Shrd": Sharada,
+		"hi-IN":    Devanagari,
+		"sa_Gran":  Grantha,
+	} {
+		if got, err := DefaultScriptFor(tag); err != nil || got != want {
+			t.Errorf("DefaultScriptFor(%s) = %s, %v, want %s", tag, got, err, want)
+		}
+	}
+
+	if _, err := roman(ctx, fake, "text", "sa-Sidd", DefaultOptions()); err != nil || fake.from != Siddham || fake.to != ISO {
+		t.Errorf("roman(sa-Sidd) = %s → %s, %v", fake.from, fake.to, err)
+	}
+
+	if _, err := roman(ctx, fake, "text", "pa-Deva", DefaultOptions()); err == nil || !strings.Contains(err.Error(), "Gurmukhi, Shahmukhi") {
+		t.Errorf("roman(pa-Deva) error = %v, want the scripts of Punjabi listed", err)
+	}
+	if _, err := roman(ctx, fake, "text", "not-a-tag-!", DefaultOptions()); err == nil {
+		t.Error("roman() with a malformed tag should fail")
+	}
+}
+
+func TestRomanDetectsScripts(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		lang, text string
+		scripts    []Script
+		want       string
+	}{
+		{"jpn", "カタカナ", []Script{Katakana}, "fake:カタカナ"},
+		{"jpn", "ひらがな カタカナ!", []Script{Hiragana, Katakana}, "fake:ひらがな fake:カタカナ!"},
+		{"jpn", "Tokyo", []Script{Hiragana}, "fake:Tokyo"},
+		{"ja-Kana", "ひらがな", []Script{Katakana}, "fake:ひらがな"},
+	}
+	for _, tt := range tests {
+		fake := &fakeTransliterator{}
+		res, err := romanDetailed(ctx, fake, tt.text, tt.lang, StyleAcademic, DefaultOptions())
+		if err != nil {
+			t.Errorf("romanDetailed(%s, %q) error = %v", tt.lang, tt.text, err)
+			continue
+		}
+		if !slices.Equal(res.Scripts, tt.scripts) || res.Text != tt.want {
+			t.Errorf("romanDetailed(%s, %q) = %q from %v, want %q from %v", tt.lang, tt.text, res.Text, res.Scripts, tt.want, tt.scripts)
+		}
+		for _, call := range fake.calls {
+			if !slices.Contains(tt.scripts, call.From) {
+				t.Errorf("romanDetailed(%s, %q) converted %q from %s", tt.lang, tt.text, call.Text, call.From)
+			}
+		}
+	}
+}
+
+func TestRomanSpans(t *testing.T) {
+	tests := []struct {
+		lang, text string
+		want       []romanSpan
+	}{
+		{"pan", "ساڈا پنجاب", []romanSpan{{"ساڈا پنجاب", Shahmukhi}}},
+		{"pan", "ਪੰਜਾਬ, ساڈا", []romanSpan{{"ਪੰਜਾਬ", Gurmukhi}, {", ", ""}, {"ساڈا", Shahmukhi}}},
+		{"pan", "Punjab", []romanSpan{{"Punjab", Gurmukhi}}},
+		{"urd", "یہ میرا گھر ہے", []romanSpan{{"یہ میرا گھر ہے", Urdu}}},
+		{"hin", "नमस्ते", []romanSpan{{"नमस्ते", Devanagari}}},
+		{"syr", "ܫܠܡܐ", []romanSpan{{"ܫܠܡܐ", Syre}}},
+	}
+	for _, tt := range tests {
+		spans, err := romanSpans(tt.text, tt.lang)
+		if err != nil || !slices.Equal(spans, tt.want) {
+			t.Errorf("romanSpans(%q, %s) = %v, %v, want %v", tt.text, tt.lang, spans, err, tt.want)
+		}
+	}
+}
+
+func TestRomanizationCoverage(t *testing.T) {
+	// The schemes are checked against the measured capability matrix, not against
+	// canRomanize which picks them
+	unsupported := func(source, scheme Script) bool {
+		c := Supports(source, scheme)
+		return c.Rated && !c.Supported
+	}
+	if len(capabilityMatrix) == 0 {
+		t.Log("capabilities.txt holds no measurements, romanization schemes are left unchecked")
+	}
+	for source, scheme := range Script2RomanScheme {
+		if unsupported(Script(source), Script(scheme)) {
+			t.Errorf("Script2RomanScheme maps %s to %s, which the capability matrix rates unsupported", source, scheme)
+		}
+	}
+
+	ctx := context.Background()
+	for lang, scripts := range Lang2Scripts {
+		for _, s := range scripts {
+			scheme, err := romanScheme(Script(s), RomanProfile{})
+			if err != nil {
+				t.Errorf("%s (%s): %v", lang, s, err)
+				continue
+			}
+			if unsupported(Script(s), scheme) {
+				t.Errorf("%s (%s) romanizes to %s, which the capability matrix rates unsupported", lang, s, scheme)
+			}
+		}
+
+		fake := &fakeTransliterator{}
+		if _, err := roman(ctx, fake, "text", lang, DefaultOptions()); err != nil {
+			t.Errorf("roman(%s) error = %v", lang, err)
+		}
+	}
+
+	for source, want := range map[Script]Script{Newa: ISO, Ethi: Latn, Urdu: Latn, Shahmukhi: Latn, Avestan: ISO} {
+		if got, _ := romanScheme(source, RomanProfile{}); got != want {
+			t.Errorf("romanScheme(%s) = %s, want %s", source, got, want)
+		}
+	}
+}
+
+var (
+	updateCapabilities = flag.Bool("update-capabilities", false, "regenerate capabilities.txt")
+	probeURL           = flag.String("probe", "", "with -update-capabilities, the API URL of the backend to measure")
+	updateOptions      = flag.Bool("update-options", false, "regenerate options.txt")
+	probeOptions       = flag.String("probe-options", "", "with -update-options, list the options of the backend running in this container")
+)
+
+// capabilityCorpus is converted into each source script to probe the pairs
+const capabilityCorpus = "dharmakṣetre kurukṣetre samavetā yuyutsavaḥ māmakāḥ pāṇḍavāścaiva kimakurvata sañjaya"
+
+// TestCapabilityMatrix checks the embedded matrix, or measures it with
+// go test -run TestCapabilityMatrix -update-capabilities -probe http://localhost:8085/api/public
+func TestCapabilityMatrix(t *testing.T) {
+	if *updateCapabilities {
+		if *probeURL == "" {
+			t.Fatal("-update-capabilities needs -probe with the API URL of a running backend")
+		}
+		header := "# Capability matrix, see Supports. Measured by\n" +
+			"#   go test -run TestCapabilityMatrix -update-capabilities -probe <API URL>\n" +
+			"# DO NOT EDIT. Source: probe " + *probeURL + "\n" +
+			"# R round-trip, L lossy, S supported, - unsupported\n"
+		data := formatCapabilities(header, Scripts(), probeCapabilities(t, *probeURL))
+		if err := os.WriteFile("capabilities.txt", []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	// A measured matrix covers every pair, an unprobed one none
+	if len(capabilityMatrix) > 0 {
+		for _, from := range Scripts() {
+			for _, to := range Scripts() {
+				if _, ok := capabilityMatrix[from][to]; !ok {
+					t.Fatalf("capabilities.txt lacks %s → %s, measure it again with -update-capabilities", from, to)
+				}
+			}
+		}
+	} else if c := Supports(Devanagari, Tamil); c.Rated || c.LowQuality() {
+		t.Errorf("Supports(Devanagari, Tamil) without measurements = %s, want unrated", c)
+	}
+
+	if c := Supports("Klingon", Devanagari); !c.Rated || c.Supported || !c.LowQuality() {
+		t.Errorf("Supports(Klingon, Devanagari) = %s, want unsupported", c)
+	}
+	if c := Supports(Thai, ISO); !c.IndicSubset {
+		t.Errorf("Supports(Thai, ISO) = %s, want indic-subset from the registry", c)
+	}
+
+	m, err := parseCapabilities("# test\nDevanagari Tamil\nDevanagari RL\nTamil S-\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := m[Devanagari][Tamil]; !c.Rated || !c.Lossy || c.String() != "lossy" {
+		t.Errorf("parsed Devanagari → Tamil = %s, want lossy", c)
+	}
+	if c := m[Tamil][Tamil]; !c.Rated || c.Supported || c.String() != "unsupported" {
+		t.Errorf("parsed Tamil → Tamil = %s, want unsupported", c)
+	}
+}
+
+// listBackendOptions lists the functions of the backend's PreProcess and
+// PostProcess modules, which are the names it accepts as pre- and post-options
+const listBackendOptions = `
+import inspect
+from aksharamukha import PreProcess, PostProcess
+for kind, mod in (("pre", PreProcess), ("post", PostProcess)):
+    for name, fn in inspect.getmembers(mod, inspect.isfunction):
+        if fn.__module__ == mod.__name__ and not name.startswith("_"):
+            print(kind, name)
+`
+
+// TestOptionCatalog checks options.txt, or regenerates it with
+// go test -run TestOptionCatalog -update-options [-probe-options aksharamukha-back-1]
+func TestOptionCatalog(t *testing.T) {
+	var header strings.Builder
+	for _, line := range strings.SplitAfter(optionData, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		header.WriteString(line)
+	}
+	pre, post, err := parseOptionCatalog(optionData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *probeOptions != "" {
+		out, err := exec.Command("docker", "exec", *probeOptions, "python3", "-c", listBackendOptions).Output()
+		if err != nil {
+			t.Fatalf("listing the backend's options: %v", err)
+		}
+		listedPre, listedPost := make(map[PreOption]OptionInfo), make(map[PostOption]OptionInfo)
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			kind, name, _ := strings.Cut(line, " ")
+			if kind == "pre" {
+				info, ok := pre[PreOption(name)]
+				if !ok {
+					info = OptionInfo{Name: name}
+				}
+				listedPre[PreOption(name)] = info
+			} else {
+				info, ok := post[PostOption(name)]
+				if !ok {
+					info = OptionInfo{Name: name}
+				}
+				listedPost[PostOption(name)] = info
+			}
+		}
+		pre, post = listedPre, listedPost
+	}
+
+	var preInfos, postInfos []OptionInfo
+	for _, name := range slices.Sorted(maps.Keys(pre)) {
+		preInfos = append(preInfos, pre[name])
+	}
+	for _, name := range slices.Sorted(maps.Keys(post)) {
+		postInfos = append(postInfos, post[name])
+	}
+	data := formatOptionCatalog(header.String(), preInfos, postInfos)
+	if *updateOptions {
+		if err := os.WriteFile("options.txt", []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if data != optionData {
+		t.Error("options.txt is not in canonical form, regenerate it with -update-options")
+	}
+
+	for _, info := range append(preInfos, postInfos...) {
+		for _, s := range append(slices.Clone(info.Sources), info.Targets...) {
+			if !IsValidScript(s) {
+				t.Errorf("option %s refers to unknown script %q", info.Name, s)
+			}
+		}
+	}
+	for _, name := range []PostOption{PostRemoveDiacritics, PostSinhalaPali, PostTibetanRemoveBa} {
+		if _, ok := name.Info(); !ok {
+			t.Errorf("post-option %s missing from options.txt", name)
+		}
+	}
+}
+
+// probeCapabilities rates each pair by converting the corpus into the source
+// script, then into the target and back
+func probeCapabilities(t *testing.T, url string) func(from, to Script) Capability {
+	am := &AksharamukhaManager{baseURL: url, initialized: true}
+	ctx := context.Background()
+	opts := TranslitOptions{Nativize: NativizeOff}
+
+	samples := make(map[Script]string)
+	for _, s := range Scripts() {
+		if sample, err := am.Translit(ctx, capabilityCorpus, ISO, s, opts); err == nil {
+			samples[s] = sample
+		} else {
+			t.Logf("no sample in %s: %v", s, err)
+		}
+	}
+
+	return func(from, to Script) Capability {
+		sample, ok := samples[from]
+		if !ok {
+			return Capability{Rated: true}
+		}
+		c := Capability{Rated: true}
+		out, err := am.Translit(ctx, sample, from, to, opts)
+		if err != nil {
+			return c
+		}
+		c.Supported = true
+		if back, err := am.Translit(ctx, out, to, from, opts); err == nil && back == sample {
+			c.RoundTrip = true
+		} else {
+			c.Lossy = true
+		}
+		return c
+	}
+}
+
+func TestPairPolicy(t *testing.T) {
+	measured, err := parseCapabilities("Devanagari Tamil ISO\nDevanagari RLR\nHebrew -LS\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(m map[Script]map[Script]Capability) { capabilityMatrix = m }(capabilityMatrix)
+	capabilityMatrix = measured
+
+	calls := 0
+	am := newTestManager(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte("ok"))
+	})
+	ctx := context.Background()
+
+	opts := TranslitOptions{PairPolicy: PairRefuse}
+	if _, err := am.Translit(ctx, "धर्म", Devanagari, Tamil, opts); err == nil || !strings.Contains(err.Error(), "lossy") {
+		t.Errorf("refused pair error = %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("refused pair reached the backend %d times", calls)
+	}
+	if _, err := am.Translit(ctx, "धर्म", Devanagari, ISO, opts); err != nil {
+		t.Errorf("round-trip pair error = %v", err)
+	}
+	if _, err := am.Translit(ctx, "ธรรม", Thai, Tamil, opts); err != nil {
+		t.Errorf("unrated pair error = %v, want it let through", err)
+	}
+
+	var warnings []Warning
+	opts = TranslitOptions{PairPolicy: PairWarn, OnWarning: func(w Warning) { warnings = append(warnings, w) }}
+	if _, err := am.Translit(ctx, "שלום", Hebrew, Tamil, opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || warnings[0].Kind != WarnLowQualityPair {
+		t.Errorf("warnings = %v, want one low-quality-pair", warnings)
+	}
+}
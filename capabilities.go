@@ -0,0 +1,196 @@
+package aksharamukha
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The backend converts through one of two engines: the Semitic one for the
+// abjads, Ethiopic and Old Persian, romanizing to its own Latn scheme and a few
+// language-specific standards, and the Indic one for every other script,
+// romanizing to the Indological schemes.
+var (
+	semiticRomanSchemes = []Script{Latn, ISO233, ISO259, HebrewSBL, PersianDMG}
+	indicRomanSchemes   = scriptsWhere(func(info ScriptInfo) bool {
+		return info.Category.Has(CategoryRoman) && !slices.Contains(semiticRomanSchemes, info.Script)
+	})
+)
+
+// romanFallback is the chain of schemes tried, in order, for the scripts that
+// have no Script2RomanScheme entry
+var romanFallback = []Script{ISO, IAST, Latn}
+
+// semiticEngine reports whether the backend converts the script with its Semitic engine
+func semiticEngine(s Script) bool {
+	switch s {
+	case Ethi, OldPersian:
+		return true
+	}
+	return s.Category().Has(CategorySemitic) || slices.Contains(semiticRomanSchemes, s)
+}
+
+// canRomanize reports whether the backend romanizes source into scheme
+func canRomanize(source, scheme Script) bool {
+	if !IsValidScript(source) || source == scheme {
+		return false
+	}
+	if semiticEngine(source) {
+		return slices.Contains(semiticRomanSchemes, scheme)
+	}
+	return slices.Contains(indicRomanSchemes, scheme)
+}
+
+// Capability rates the conversion of one script into another
+type Capability struct {
+	// Rated: the pair was measured against a backend, or involves a script this
+	// package does not know (rated unsupported). The three fields below are only
+	// meaningful for rated pairs.
+	Rated     bool
+	Supported bool
+	// RoundTrip: converting the output back gives the input again
+	RoundTrip bool
+	// Lossy: the target cannot keep some distinctions of the source
+	Lossy bool
+	// IndicSubset: one of the scripts is only handled for its Indic/Pali subset,
+	// as recorded in the script registry rather than measured
+	IndicSubset bool
+}
+
+func (c Capability) String() string {
+	s := "unrated"
+	switch {
+	case !c.Rated:
+	case c.RoundTrip:
+		s = "round-trip"
+	case c.Lossy:
+		s = "lossy"
+	case c.Supported:
+		s = "supported"
+	default:
+		s = "unsupported"
+	}
+	if c.IndicSubset {
+		s += ", indic-subset"
+	}
+	return s
+}
+
+// LowQuality reports whether the pair is rated unsupported or lossy, unrated
+// pairs are not
+func (c Capability) LowQuality() bool {
+	return c.Rated && (!c.Supported || c.Lossy)
+}
+
+// capabilityCode is the letter of a capability in capabilities.txt: R round-trip,
+// L lossy, S supported, - unsupported
+func capabilityCode(c Capability) byte {
+	switch {
+	case c.RoundTrip:
+		return 'R'
+	case c.Lossy:
+		return 'L'
+	case c.Supported:
+		return 'S'
+	}
+	return '-'
+}
+
+func parseCapabilityCode(code byte) (Capability, error) {
+	c := Capability{Rated: true}
+	switch code {
+	case 'R':
+		c.Supported, c.RoundTrip = true, true
+	case 'L':
+		c.Supported, c.Lossy = true, true
+	case 'S':
+		c.Supported = true
+	case '-':
+	default:
+		return c, fmt.Errorf("unknown capability code %q", code)
+	}
+	return c, nil
+}
+
+//go:embed capabilities.txt
+var capabilityData string
+
+// capabilityMatrix holds the embedded capabilities, by source then target
+var capabilityMatrix = func() map[Script]map[Script]Capability {
+	m, err := parseCapabilities(capabilityData)
+	if err != nil {
+		panic("capabilities.txt: " + err.Error())
+	}
+	return m
+}()
+
+// Supports rates the conversion of from into to according to the embedded
+// capability matrix, measured by converting a test corpus with a running backend
+// (see TestCapabilityMatrix). Pairs the matrix does not cover are unrated, and
+// pairs involving unknown scripts are rated unsupported.
+func Supports(from, to Script) Capability {
+	if !IsValidScript(from) || !IsValidScript(to) {
+		return Capability{Rated: true}
+	}
+	c := capabilityMatrix[from][to]
+	for _, s := range []Script{from, to} {
+		if info, _ := s.Info(); info.Support == SupportIndicSubset {
+			c.IndicSubset = true
+		}
+	}
+	return c
+}
+
+// parseCapabilities reads capabilities.txt: comment lines start with #, the first
+// other line lists the target scripts, then each line holds a source script and
+// one capability code per target. A file with comments only rates nothing.
+func parseCapabilities(data string) (map[Script]map[Script]Capability, error) {
+	var targets []string
+	m := make(map[Script]map[Script]Capability)
+	for i, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if targets == nil {
+			targets = fields
+			continue
+		}
+		if len(fields) != 2 || len(fields[1]) != len(targets) {
+			return nil, fmt.Errorf("line %d: want a script and %d codes", i+1, len(targets))
+		}
+		row := make(map[Script]Capability, len(targets))
+		for j, target := range targets {
+			c, err := parseCapabilityCode(fields[1][j])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			row[Script(target)] = c
+		}
+		m[Script(fields[0])] = row
+	}
+	return m, nil
+}
+
+// formatCapabilities writes a matrix in the format read by parseCapabilities
+func formatCapabilities(header string, scripts []Script, rate func(from, to Script) Capability) string {
+	var b strings.Builder
+	b.WriteString(header)
+	for i, s := range scripts {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(string(s))
+	}
+	b.WriteByte('\n')
+	for _, from := range scripts {
+		b.WriteString(string(from))
+		b.WriteByte(' ')
+		for _, to := range scripts {
+			b.WriteByte(capabilityCode(rate(from, to)))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,201 @@
+package aksharamukha
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+)
+
+// ScriptGuess is a candidate source script with the share of the text's letters
+// attributed to it, between 0 and 1
+type ScriptGuess struct {
+	Script     Script
+	Confidence float64
+}
+
+// detectHint is a letter found in only some of the scripts sharing a block,
+// each occurrence adds its boost to the weight of those scripts
+type detectHint struct {
+	lo, hi rune
+	boost  map[Script]int
+}
+
+var detectHints = []detectHint{
+	// Arabic-only letters: tāʾ marbūṭa, alif maqṣūra, Arabic yāʾ and kāf
+	{0x0629, 0x0629, map[Script]int{Arab: 1}},
+	{0x0643, 0x0643, map[Script]int{Arab: 1}},
+	{0x0649, 0x064A, map[Script]int{Arab: 1}},
+	// pe, che, zhe, gaf, Persian kaf and yeh, shared by Persian, Urdu and Shahmukhi
+	{0x067E, 0x067E, map[Script]int{ArabFa: 1, Urdu: 1, Shahmukhi: 1}},
+	{0x0686, 0x0686, map[Script]int{ArabFa: 1, Urdu: 1, Shahmukhi: 1}},
+	{0x0698, 0x0698, map[Script]int{ArabFa: 1, Urdu: 1, Shahmukhi: 1}},
+	{0x06A9, 0x06A9, map[Script]int{ArabFa: 1, Urdu: 1, Shahmukhi: 1}},
+	{0x06AF, 0x06AF, map[Script]int{ArabFa: 1, Urdu: 1, Shahmukhi: 1}},
+	{0x06CC, 0x06CC, map[Script]int{ArabFa: 1, Urdu: 1, Shahmukhi: 1}},
+	// retroflexes, noon ghunna, do-chashmi and goal heh, bari yeh: Urdu, which
+	// Shahmukhi follows, so Urdu is favoured unless Shahmukhi-only letters appear
+	{0x0679, 0x0679, map[Script]int{Urdu: 2, Shahmukhi: 1}},
+	{0x0688, 0x0688, map[Script]int{Urdu: 2, Shahmukhi: 1}},
+	{0x0691, 0x0691, map[Script]int{Urdu: 2, Shahmukhi: 1}},
+	{0x06BA, 0x06BA, map[Script]int{Urdu: 2, Shahmukhi: 1}},
+	{0x06BE, 0x06BE, map[Script]int{Urdu: 2, Shahmukhi: 1}},
+	{0x06C1, 0x06C1, map[Script]int{Urdu: 2, Shahmukhi: 1}},
+	{0x06D2, 0x06D2, map[Script]int{Urdu: 2, Shahmukhi: 1}},
+	// Punjabi nasal noon and velarized lam
+	{0x0768, 0x0768, map[Script]int{Shahmukhi: 3}},
+	{0x06B5, 0x06B5, map[Script]int{Shahmukhi: 3}},
+	// Assamese ra and wa, Bengali ra
+	{0x09F0, 0x09F1, map[Script]int{Assamese: 1}},
+	{0x09B0, 0x09B0, map[Script]int{Bengali: 1}},
+	// Myanmar letters specific to Mon, Shan, Khamti Shan and Tai Laing
+	{0x105A, 0x1060, map[Script]int{Mon: 1}},
+	{0x1075, 0x108F, map[Script]int{Shan: 1, KhamtiShan: 1, TaiLaing: 1}},
+	{0xAA60, 0xAA7F, map[Script]int{KhamtiShan: 1}},
+	{0xA9E0, 0xA9FF, map[Script]int{TaiLaing: 1, Shan: 1}},
+	// Lao letters and virama added for Pali
+	{0x0E86, 0x0E86, map[Script]int{LaoPali: 1}},
+	{0x0E89, 0x0E89, map[Script]int{LaoPali: 1}},
+	{0x0E8C, 0x0E8C, map[Script]int{LaoPali: 1}},
+	{0x0E8E, 0x0E93, map[Script]int{LaoPali: 1}},
+	{0x0E98, 0x0E98, map[Script]int{LaoPali: 1}},
+	{0x0EA0, 0x0EA0, map[Script]int{LaoPali: 1}},
+	{0x0EA8, 0x0EA9, map[Script]int{LaoPali: 1}},
+	{0x0EAC, 0x0EAC, map[Script]int{LaoPali: 1}},
+	{0x0EBA, 0x0EBA, map[Script]int{LaoPali: 1}},
+	// Tamil Brahmi letters
+	{0x11070, 0x11075, map[Script]int{TamilBrahmi: 1}},
+	// geresh, marking Arabic sounds in Judeo-Arabic
+	{0x05F3, 0x05F3, map[Script]int{HebrAr: 1}},
+}
+
+// detectRanges overrides the registry ranges for detection: the romanization
+// schemes are all credited to Latn since code points cannot tell them apart, IPA
+// only claims its own letters and the prolonged sound mark belongs to both kana.
+var detectRanges = map[Script][]UnicodeRange{
+	IPA:      {{0x0250, 0x02AF}},
+	Hiragana: {{0x3040, 0x309F}, {0x30FC, 0x30FC}},
+}
+
+// detectPrimary are the modern scripts that stand for a block shared with
+// historic or liturgical variants: when the letters cannot tell them apart, the
+// text is taken to be in the primary script. Blocks without one (Batak, Syriac)
+// are left undecided.
+var detectPrimary = map[Script]bool{
+	Arab: true, Bengali: true, Brahmi: true, Burmese: true, Grantha: true,
+	Hebrew: true, Thai: true, Lao: true, Tamil: true, TaiTham: true,
+}
+
+// detectGroup is a set of scripts sharing the same code points
+type detectGroup struct {
+	ranges  []UnicodeRange
+	scripts []Script
+}
+
+var detectGroups = buildDetectGroups()
+
+func buildDetectGroups() []*detectGroup {
+	var groups []*detectGroup
+	byRanges := make(map[string]*detectGroup)
+	for _, info := range scriptTable {
+		ranges, ok := detectRanges[info.Script]
+		if !ok {
+			if info.Category.Has(CategoryRoman) && info.Script != Latn {
+				continue
+			}
+			ranges = info.Ranges
+		}
+		if len(ranges) == 0 {
+			continue
+		}
+		key := fmt.Sprint(ranges)
+		g, ok := byRanges[key]
+		if !ok {
+			g = &detectGroup{ranges: ranges}
+			byRanges[key] = g
+			groups = append(groups, g)
+		}
+		g.scripts = append(g.scripts, info.Script)
+	}
+	return groups
+}
+
+// DetectScript ranks the scripts text may be written in, from the Unicode ranges
+// of its letters; digits, punctuation, spaces and symbols are ignored. Scripts
+// sharing a block split the share of that block, weighted by the letters only
+// some of them use (e.g. ے for Urdu over Persian). Ties put the primary script
+// of a block first (Thai before Khom Thai), then keep registry order.
+// Latin text is reported as Latn whatever the romanization scheme.
+func DetectScript(text string) []ScriptGuess {
+	total := 0
+	counts := make([]int, len(detectGroups))
+	hits := make(map[Script]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) {
+			continue
+		}
+		total++
+		for i, g := range detectGroups {
+			if inRanges(r, g.ranges) {
+				counts[i]++
+			}
+		}
+		for _, h := range detectHints {
+			if r >= h.lo && r <= h.hi {
+				for s, n := range h.boost {
+					hits[s] += n
+				}
+			}
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var guesses []ScriptGuess
+	for i, g := range detectGroups {
+		if counts[i] == 0 {
+			continue
+		}
+		share := float64(counts[i]) / float64(total)
+		sum := 0
+		for _, s := range g.scripts {
+			sum += 1 + hits[s]
+		}
+		for _, s := range g.scripts {
+			guesses = append(guesses, ScriptGuess{
+				Script:     s,
+				Confidence: share * float64(1+hits[s]) / float64(sum),
+			})
+		}
+	}
+	sort.SliceStable(guesses, func(i, j int) bool {
+		if guesses[i].Confidence != guesses[j].Confidence {
+			return guesses[i].Confidence > guesses[j].Confidence
+		}
+		return detectPrimary[guesses[i].Script] && !detectPrimary[guesses[j].Script]
+	})
+	return guesses
+}
+
+// detectSource picks the source script of text, empty if it has no letters, is
+// Latin, whose romanization scheme cannot be told from code points, or if the
+// best guesses tie without a single primary script among them to settle it
+func detectSource(text string) Script {
+	guesses := DetectScript(text)
+	if len(guesses) == 0 || guesses[0].Script == Latn {
+		return ""
+	}
+	primaries := 0
+	for _, g := range guesses {
+		if g.Confidence != guesses[0].Confidence {
+			break
+		}
+		if detectPrimary[g.Script] {
+			primaries++
+		}
+	}
+	if len(guesses) > 1 && guesses[1].Confidence == guesses[0].Confidence && primaries != 1 {
+		return ""
+	}
+	return guesses[0].Script
+}
@@ -2,11 +2,14 @@ package aksharamukha
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/client"
 	"github.com/gookit/color"
 	"github.com/k0kubun/pp"
 	"github.com/rs/zerolog"
@@ -23,6 +26,9 @@ const (
 )
 
 var (
+	// ErrClosed is returned by requests made on a manager that was shut down
+	ErrClosed = errors.New("aksharamukha manager is closed")
+
 	DefaultQueryTimeout   = 5 * time.Minute
 	DefaultDockerLogLevel = zerolog.TraceLevel
 
@@ -49,11 +55,47 @@ func ClearDownloadProgressCallback() {
 // AksharamukhaManager handles Docker lifecycle for Aksharamukha project
 type AksharamukhaManager struct {
 	docker                   *dockerutil.DockerManager
+	dockerCfg                dockerutil.Config
 	logger                   *dockerutil.ContainerLogConsumer
 	projectName              string
 	backContainer            string
 	QueryTimeout             time.Duration
 	downloadProgressCallback func(current, total int64, status string)
+	logSink                  io.Writer
+	logFunc                  func(LogLine)
+	romanProfiles            map[string]RomanProfile
+	maxConcurrency           int
+	rules                    *RuleSet
+	middleware               []Middleware
+	// overrides the API endpoint, for tests
+	baseURL string
+
+	// lifecycle state guarding Shutdown against in-flight requests
+	stateMu   sync.Mutex
+	closed    bool
+	inflight  sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+	// stops started by Stop that may outlive its context
+	stopping sync.WaitGroup
+
+	// auto-init state, see WithAutoInit
+	autoInit    bool
+	initMu      sync.Mutex
+	initialized bool
+	initAttempt *initAttempt
+	// brings the container up for ensureInit, InitQuiet unless overridden by tests
+	initFunc func(context.Context) error
+	// identifies the running backend image, see BackendVersion; resolved once
+	// per Init, failures included, so that conversions never inspect the container
+	backendVersion  string
+	versionResolved bool
+}
+
+// initAttempt is a container startup shared by all callers waiting on it
+type initAttempt struct {
+	done chan struct{}
+	err  error
 }
 
 // ManagerOption defines function signature for options to configure AksharamukhaManager
@@ -81,6 +123,14 @@ func WithContainerName(name string) ManagerOption {
 	}
 }
 
+// WithAutoInit makes the first Translit call bring up the container if Init
+// was not called beforehand. Concurrent first callers share a single startup.
+func WithAutoInit() ManagerOption {
+	return func(am *AksharamukhaManager) {
+		am.autoInit = true
+	}
+}
+
 // WithDownloadProgressCallback sets a callback for download progress during image pull
 func WithDownloadProgressCallback(cb func(current, total int64, status string)) ManagerOption {
 	return func(am *AksharamukhaManager) {
@@ -147,11 +197,20 @@ func NewManager(ctx context.Context, opts ...ManagerOption) (*AksharamukhaManage
 
 	logger := dockerutil.NewContainerLogConsumer(logConfig)
 
+	var consumer dockerutil.LogConsumer = logger
+	if manager.logSink != nil || manager.logFunc != nil {
+		consumer = &logTee{
+			ContainerLogConsumer: logger,
+			sink:                 manager.logSink,
+			fn:                   manager.logFunc,
+		}
+	}
+
 	cfg := dockerutil.Config{
 		ProjectName:      manager.projectName,
 		Project:          project,
 		RequiredServices: []string{"back"},
-		LogConsumer:      logger,
+		LogConsumer:      consumer,
 		Timeout: dockerutil.Timeout{
 			Create:   60 * time.Second,
 			Recreate: 10 * time.Minute,
@@ -166,6 +225,7 @@ func NewManager(ctx context.Context, opts ...ManagerOption) (*AksharamukhaManage
 	}
 
 	manager.docker = dockerManager
+	manager.dockerCfg = cfg
 	manager.logger = logger
 
 	return manager, nil
@@ -173,20 +233,138 @@ func NewManager(ctx context.Context, opts ...ManagerOption) (*AksharamukhaManage
 
 // Init initializes the docker service
 func (am *AksharamukhaManager) Init(ctx context.Context) error {
-	return am.docker.Init()
+	return am.withDocker(ctx, (*dockerutil.DockerManager).Init)
 }
 
 // InitQuiet initializes the docker service with reduced logging
 func (am *AksharamukhaManager) InitQuiet(ctx context.Context) error {
-	return am.docker.InitQuiet()
+	return am.withDocker(ctx, (*dockerutil.DockerManager).InitQuiet)
 }
 
 // InitRecreate remove existing containers then builds and up the containers
 func (am *AksharamukhaManager) InitRecreate(ctx context.Context, noCache bool) error {
 	if noCache {
-		return am.docker.InitRecreateNoCache()
+		return am.withDocker(ctx, (*dockerutil.DockerManager).InitRecreateNoCache)
+	}
+	return am.withDocker(ctx, (*dockerutil.DockerManager).InitRecreate)
+}
+
+// withDocker runs a lifecycle operation bound to ctx: dockerutil captures the
+// context at construction, so a short-lived DockerManager sharing this manager's
+// config and log consumer is created for the call. Image pull, create, start and
+// the wait for readiness all observe ctx, see detachOnSuccess. If ctx ends midway
+// and the container did not exist before the call, whatever was half-created is
+// torn down; a container that already existed is left as it is.
+//
+// DockerManager.Close stops the compose project, so the per-call manager is not
+// closed: it only holds a Docker CLI, whose idle connections are released with it.
+func (am *AksharamukhaManager) withDocker(ctx context.Context, op func(*dockerutil.DockerManager) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	existed := am.containerExists(ctx)
+
+	var dm *dockerutil.DockerManager
+	err := detachOnSuccess(ctx, func(opCtx context.Context) error {
+		var err error
+		if dm, err = dockerutil.NewDockerManager(opCtx, am.dockerCfg); err != nil {
+			return fmt.Errorf("failed to create Docker manager: %w", err)
+		}
+		return op(dm)
+	})
+	if err != nil {
+		if ctx.Err() != nil && dm != nil && !existed {
+			if downErr := dm.Down(); downErr != nil {
+				return fmt.Errorf("%w (cleanup after cancellation failed: %v)", ctx.Err(), downErr)
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	version := am.inspectBackendVersion(ctx)
+	am.initMu.Lock()
+	am.initialized = true
+	am.backendVersion, am.versionResolved = version, true
+	am.initMu.Unlock()
+	return nil
+}
+
+// detachOnSuccess runs op on a context that ctx cancels only until op returns.
+// Compose keeps the containers attached to the context of Up after they are
+// ready, for the log consumer, and tears the stack down once it ends: a caller
+// cancelling ctx after a successful Init must neither stop the container nor
+// silence its logs. The context of a successful op is therefore never cancelled.
+// Later Inits finding the container running do not attach again, so the log
+// consumer sees each line once.
+func detachOnSuccess(ctx context.Context, op func(context.Context) error) error {
+	opCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, cancel)
+	err := op(opCtx)
+	stop()
+	return err
+}
+
+// containerExists reports whether the back container exists, running or not.
+// When Docker cannot tell, it is assumed to exist so that nothing is torn down.
+func (am *AksharamukhaManager) containerExists(ctx context.Context) bool {
+	cli, err := am.docker.GetClient()
+	if err != nil {
+		return true
+	}
+	defer cli.Close()
+	_, err = cli.ContainerInspect(ctx, am.backContainer)
+	return !client.IsErrNotFound(err)
+}
+
+func (am *AksharamukhaManager) isInitialized() bool {
+	am.initMu.Lock()
+	defer am.initMu.Unlock()
+	return am.initialized
+}
+
+// ensureInit brings up the container once for auto-init managers. A startup that
+// is already running is joined rather than duplicated; a failed one is retried on
+// the next call. The startup itself is detached from ctx so that one caller
+// giving up does not abort it for the others.
+func (am *AksharamukhaManager) ensureInit(ctx context.Context) error {
+	if !am.autoInit {
+		return nil
+	}
+
+	am.initMu.Lock()
+	if am.initialized {
+		am.initMu.Unlock()
+		return nil
+	}
+	attempt := am.initAttempt
+	if attempt == nil {
+		attempt = &initAttempt{done: make(chan struct{})}
+		am.initAttempt = attempt
+		init := am.initFunc
+		if init == nil {
+			init = am.InitQuiet
+		}
+		go func() {
+			attempt.err = init(context.Background())
+			am.initMu.Lock()
+			am.initAttempt = nil
+			am.initMu.Unlock()
+			close(attempt.done)
+		}()
+	}
+	am.initMu.Unlock()
+
+	select {
+	case <-attempt.done:
+		if attempt.err != nil {
+			return fmt.Errorf("auto-init failed: %w", attempt.err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return am.docker.InitRecreate()
 }
 
 // PullImages pre-pulls all required Docker images with retry logic.
@@ -218,34 +396,180 @@ func (am *AksharamukhaManager) PullImages(ctx context.Context) error {
 
 // MustInit initializes the docker service and panics on error
 func (am *AksharamukhaManager) MustInit(ctx context.Context) {
-	if err := am.docker.InitRecreate(); err != nil {
+	if err := am.InitRecreate(ctx, false); err != nil {
 		panic(err)
 	}
 }
 
-// Stop stops the docker service
+// Stop stops the docker service. If ctx ends first, Stop returns its error
+// while the stop request carries on in the background; Close and Shutdown
+// wait for it to finish.
 func (am *AksharamukhaManager) Stop(ctx context.Context) error {
-	return am.docker.Stop()
+	am.initMu.Lock()
+	am.initialized = false
+	am.initMu.Unlock()
+
+	done := make(chan error, 1)
+	am.stopping.Add(1)
+	go func() {
+		defer am.stopping.Done()
+		done <- am.docker.Stop()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-// Close implements io.Closer
+// Close implements io.Closer. It refuses new requests and stops the container
+// right away, without waiting for in-flight requests (see Shutdown).
 func (am *AksharamukhaManager) Close() error {
-	am.logger.Close()
-	return am.docker.Close()
+	am.markClosed()
+	return am.release()
+}
+
+// Shutdown stops accepting new requests, which then fail with ErrClosed, waits
+// for in-flight requests to complete and stops the container. If ctx expires first,
+// the container is stopped anyway and the context's error is returned.
+func (am *AksharamukhaManager) Shutdown(ctx context.Context) error {
+	am.markClosed()
+
+	drained := make(chan struct{})
+	go func() {
+		am.inflight.Wait()
+		close(drained)
+	}()
+
+	var ctxErr error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		ctxErr = ctx.Err()
+	}
+
+	if err := am.release(); err != nil {
+		return err
+	}
+	return ctxErr
+}
+
+// begin registers an in-flight request, every successful call must be paired with end
+func (am *AksharamukhaManager) begin() error {
+	am.stateMu.Lock()
+	defer am.stateMu.Unlock()
+	if am.closed {
+		return ErrClosed
+	}
+	am.inflight.Add(1)
+	return nil
+}
+
+func (am *AksharamukhaManager) end() {
+	am.inflight.Done()
+}
+
+func (am *AksharamukhaManager) markClosed() {
+	am.stateMu.Lock()
+	am.closed = true
+	am.stateMu.Unlock()
+}
+
+// release closes the logger and stops the container exactly once, after any
+// stop left running by Stop
+func (am *AksharamukhaManager) release() error {
+	am.closeOnce.Do(func() {
+		am.stopping.Wait()
+		am.logger.Close()
+		am.closeErr = am.docker.Close()
+	})
+	return am.closeErr
 }
 
 // GetBaseURL returns the base URL for API requests
 func (am *AksharamukhaManager) GetBaseURL() string {
+	if am.baseURL != "" {
+		return am.baseURL
+	}
 	return "http://localhost:8085/api/public"
 }
 
+// Transliterator is the set of operations the package-level functions delegate to.
+// *AksharamukhaManager implements it; SetDefault accepts any implementation.
+type Transliterator interface {
+	TranslitDetailed(ctx context.Context, req Request) (*TranslitResult, error)
+	Translit(ctx context.Context, text string, from, to Script, opts TranslitOptions) (string, error)
+	Init(ctx context.Context) error
+	InitQuiet(ctx context.Context) error
+	InitRecreate(ctx context.Context, noCache bool) error
+	PullImages(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+	Close() error
+}
+
+var _ Transliterator = (*AksharamukhaManager)(nil)
+
 // For backward compatibility with existing code
 var (
-	instance *AksharamukhaManager
+	instance Transliterator
+	// whether instance was created by this package (and must be closed by it)
+	instanceOwned bool
+	// options used whenever the package creates the default manager
+	defaultManagerOptions []ManagerOption
+	// set by Shutdown: package-level calls fail with ErrClosed until a new
+	// default is installed or the old one is reset
+	defaultShutDown bool
 	mu sync.Mutex
-	instanceClosed bool
 )
 
+// SetDefault installs t as the instance used by the package-level functions.
+// A default previously created by the package is closed; one installed by the
+// caller is left untouched and remains the caller's responsibility.
+func SetDefault(t Transliterator) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	err := dropDefaultLocked()
+	instance = t
+	instanceOwned = false
+	defaultShutDown = false
+	return err
+}
+
+// Default returns the instance used by the package-level functions, creating it if needed
+func Default() (Transliterator, error) {
+	return getOrCreateDefaultManager(context.Background())
+}
+
+// ResetDefault forgets the current default instance, closing it if the package
+// created it. The next package-level call creates a fresh one, even after Shutdown.
+func ResetDefault() error {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultShutDown = false
+	return dropDefaultLocked()
+}
+
+// SetDefaultManagerOptions sets the options used when the package creates its
+// default manager. They apply to the next default created, see ResetDefault.
+func SetDefaultManagerOptions(opts ...ManagerOption) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaultManagerOptions = append([]ManagerOption(nil), opts...)
+}
+
+// dropDefaultLocked must be called with mu held
+func dropDefaultLocked() (err error) {
+	if instance != nil && instanceOwned {
+		err = instance.Close()
+	}
+	instance = nil
+	instanceOwned = false
+	return
+}
+
 // InitWithContext initializes the default docker service with a context
 func InitWithContext(ctx context.Context) error {
 	mgr, err := getOrCreateDefaultManager(ctx)
@@ -304,8 +628,13 @@ func PullImages() error {
 
 // MustInitWithContext initializes the docker service with a context (panics on error)
 func MustInitWithContext(ctx context.Context) {
-	mgr, _ := getOrCreateDefaultManager(ctx)
-	mgr.MustInit(ctx)
+	mgr, err := getOrCreateDefaultManager(ctx)
+	if err != nil {
+		panic(err)
+	}
+	if err := mgr.InitRecreate(ctx, false); err != nil {
+		panic(err)
+	}
 }
 
 // MustInit initializes the docker service (backward compatibility)
@@ -315,10 +644,14 @@ func MustInit() {
 
 // StopWithContext stops the docker service with a context
 func StopWithContext(ctx context.Context) error {
-	if instance == nil {
+	mu.Lock()
+	mgr := instance
+	mu.Unlock()
+
+	if mgr == nil {
 		return fmt.Errorf("docker instance not initialized")
 	}
-	return instance.Stop(ctx)
+	return mgr.Stop(ctx)
 }
 
 // Stop stops the docker service (backward compatibility)
@@ -326,36 +659,57 @@ func Stop() error {
 	return StopWithContext(context.Background())
 }
 
-// Close implements io.Closer (backward compatibility)
+// Close implements io.Closer (backward compatibility). Like ResetDefault, an
+// instance installed with SetDefault is only forgotten, not closed.
 func Close() error {
 	mu.Lock()
-	defer mu.Unlock()
-	
-	if instance != nil {
-		instance.logger.Close()
-		err := instance.docker.Close()
-		// Mark the instance as closed
-		instanceClosed = true
-		return err
+	mgr, owned := instance, instanceOwned
+	instance = nil
+	instanceOwned = false
+	mu.Unlock()
+
+	if mgr != nil && owned {
+		return mgr.Close()
+	}
+	return nil
+}
+
+// Shutdown drains in-flight requests of the default instance then stops it.
+// Later package-level calls fail with ErrClosed instead of creating a new
+// default, until SetDefault or ResetDefault is called. An instance installed
+// with SetDefault is only forgotten, its shutdown is left to the caller.
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	mgr, owned := instance, instanceOwned
+	instance = nil
+	instanceOwned = false
+	defaultShutDown = true
+	mu.Unlock()
+
+	if mgr != nil && owned {
+		return mgr.Shutdown(ctx)
 	}
 	return nil
 }
 
 // getOrCreateDefaultManager returns or creates the default manager instance
-func getOrCreateDefaultManager(ctx context.Context) (*AksharamukhaManager, error) {
+func getOrCreateDefaultManager(ctx context.Context) (Transliterator, error) {
 	mu.Lock()
 	defer mu.Unlock()
-	
+
+	if defaultShutDown {
+		return nil, ErrClosed
+	}
 	// Create a new instance if it doesn't exist or was previously closed
-	if instance == nil || instanceClosed {
-		mgr, err := NewManager(ctx)
+	if instance == nil {
+		mgr, err := NewManager(ctx, defaultManagerOptions...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create default manager: %w", err)
 		}
 		instance = mgr
-		instanceClosed = false
+		instanceOwned = true
 	}
-	
+
 	return instance, nil
 }
 
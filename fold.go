@@ -0,0 +1,203 @@
+package aksharamukha
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// LongVowelMode controls how vowels marked long by a macron are folded
+type LongVowelMode int
+
+const (
+	// LongVowelDrop folds ā to a
+	LongVowelDrop LongVowelMode = iota
+	// LongVowelDouble folds ā to aa
+	LongVowelDouble
+)
+
+// FoldRules configures FoldASCII
+type FoldRules struct {
+	// Replacements are applied first, longest match first. Keys are matched
+	// in lowercase and capitalized form, e.g. "ṣ": "sh" also folds Ṣ to Sh.
+	Replacements map[string]string
+	LongVowels   LongVowelMode
+}
+
+// DefaultFoldRules renders the sibilants ś and ṣ as sh, vocalic r as ri, and drops
+// every other diacritic
+var DefaultFoldRules = FoldRules{
+	Replacements: map[string]string{
+		"ś":  "sh",
+		"ṣ":  "sh",
+		"r̥̄": "ri",
+		"r̥":  "ri",
+		"ṝ":  "ri",
+		"ṛ":  "ri",
+		"l̥":  "li",
+		"ḷ":  "l",
+	},
+	LongVowels: LongVowelDrop,
+}
+
+// asciiFallback renders letters that do not decompose into an ASCII base plus marks
+var asciiFallback = map[rune]string{
+	'ʾ': "", 'ʿ': "", 'ʼ': "", 'ʻ': "", '’': "'",
+	'ı': "i", 'ł': "l", 'Ł': "L", 'đ': "d", 'Đ': "D", 'ħ': "h",
+	'ə': "e", 'ɛ': "e", 'ɔ': "o", 'ɪ': "i", 'ʊ': "u", 'æ': "ae", 'Æ': "Ae",
+	'ø': "o", 'Ø': "O", 'œ': "oe", 'ß': "ss",
+	'ŋ': "ng", 'ɲ': "ny", 'ɳ': "n", 'ʃ': "sh", 'ʂ': "sh", 'ʒ': "zh", 'ʈ': "t", 'ɖ': "d",
+	'θ': "th", 'ð': "dh", 'χ': "kh", 'ɣ': "gh", 'ʔ': "", 'ː': "",
+}
+
+// FoldASCII folds romanized text to plain ASCII: replacements from rules first,
+// then diacritics are stripped. Characters with no ASCII rendering are dropped.
+func FoldASCII(s string, rules FoldRules) string {
+	s = norm.NFC.String(s)
+	if len(rules.Replacements) > 0 {
+		s = foldReplacer(rules.Replacements).Replace(s)
+	}
+
+	var b strings.Builder
+	runes := []rune(norm.NFD.String(s))
+	for i, r := range runes {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// U+0304 is the combining macron
+			if r == '̄' && rules.LongVowels == LongVowelDouble && i > 0 && isLatinVowel(runes[i-1]) {
+				b.WriteRune(unicode.ToLower(runes[i-1]))
+			}
+		default:
+			b.WriteString(asciiFallback[r])
+		}
+	}
+	return b.String()
+}
+
+func isLatinVowel(r rune) bool {
+	return strings.ContainsRune("aeiouAEIOU", r)
+}
+
+// foldReplacer builds a replacer trying longer keys first, with capitalized variants
+func foldReplacer(replacements map[string]string) *strings.Replacer {
+	type pair struct{ from, to string }
+	var pairs []pair
+	for from, to := range replacements {
+		from = norm.NFC.String(from)
+		pairs = append(pairs, pair{from, to})
+		if upper := capitalize(from); upper != from {
+			pairs = append(pairs, pair{upper, capitalize(to)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if len(pairs[i].from) != len(pairs[j].from) {
+			return len(pairs[i].from) > len(pairs[j].from)
+		}
+		return pairs[i].from < pairs[j].from
+	})
+	oldnew := make([]string, 0, 2*len(pairs))
+	for _, p := range pairs {
+		oldnew = append(oldnew, p.from, p.to)
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// Slug folds s to ASCII and turns it into a lowercase, URL-safe slug where
+// every run of other characters becomes a single hyphen
+func Slug(s string, rules FoldRules) string {
+	folded := strings.ToLower(FoldASCII(s, rules))
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range folded {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		} else {
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// SlugSet hands out slugs that are unique within the set, appending -2, -3…
+// on collision. It is safe for concurrent use.
+type SlugSet struct {
+	rules FoldRules
+	mu    sync.Mutex
+	seen  map[string]bool
+}
+
+// NewSlugSet returns an empty set folding with the given rules
+func NewSlugSet(rules FoldRules) *SlugSet {
+	return &SlugSet{rules: rules, seen: make(map[string]bool)}
+}
+
+// Unique returns the slug of s, made unique among those already returned.
+// Text that folds to nothing gets the slug "n-a".
+func (set *SlugSet) Unique(s string) string {
+	base := Slug(s, set.rules)
+	if base == "" {
+		base = "n-a"
+	}
+
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	slug := base
+	for n := 2; set.seen[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	set.seen[slug] = true
+	return slug
+}
+
+// RomanASCII romanizes text of the given language and folds the result to ASCII
+func RomanASCII(ctx context.Context, text, languageCode string, rules FoldRules) (string, error) {
+	romanized, err := RomanWithContext(ctx, text, languageCode, DefaultOptions())
+	if err != nil {
+		return "", err
+	}
+	return FoldASCII(romanized, rules), nil
+}
+
+// RomanASCII romanizes text using this manager's profiles and folds the result to ASCII
+func (am *AksharamukhaManager) RomanASCII(ctx context.Context, text, languageCode string, rules FoldRules) (string, error) {
+	romanized, err := am.Roman(ctx, text, languageCode, DefaultOptions())
+	if err != nil {
+		return "", err
+	}
+	return FoldASCII(romanized, rules), nil
+}
+
+// TranslitASCII converts text from any supported script to its academic
+// romanization (see Script2RomanScheme) and folds it to ASCII
+func (am *AksharamukhaManager) TranslitASCII(ctx context.Context, text string, from Script, rules FoldRules) (string, error) {
+	scheme, err := romanScheme(from, RomanProfile{})
+	if err != nil {
+		return "", err
+	}
+	romanized, err := am.Translit(ctx, text, from, scheme, DefaultOptions())
+	if err != nil {
+		return "", err
+	}
+	return FoldASCII(romanized, rules), nil
+}
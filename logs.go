@@ -0,0 +1,231 @@
+package aksharamukha
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/tassa-yoniso-manasi-karoto/dockerutil"
+)
+
+// maxTracebackLines caps how many backend log lines get attached to an error
+const maxTracebackLines = 20
+
+// LogLine is a single line written by the backend container
+type LogLine struct {
+	// Stream is either "stdout" or "stderr"
+	Stream string
+	Text   string
+}
+
+// BackendError is returned when the Aksharamukha API answers with an error status.
+// For 5xx responses Traceback holds the Python traceback the back container logged
+// for the request; it is left empty when concurrent failures make it ambiguous.
+type BackendError struct {
+	StatusCode int
+	Traceback  []string
+}
+
+func (e *BackendError) Error() string {
+	if len(e.Traceback) == 0 {
+		return fmt.Sprintf("API request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API request failed with status %d:\n%s", e.StatusCode, strings.Join(e.Traceback, "\n"))
+}
+
+// WithContainerLogSink mirrors the back container's stdout and stderr to w, one line at a time
+func WithContainerLogSink(w io.Writer) ManagerOption {
+	return func(am *AksharamukhaManager) {
+		am.logSink = w
+	}
+}
+
+// WithContainerLogFunc calls fn for every line written by the back container
+func WithContainerLogFunc(fn func(LogLine)) ManagerOption {
+	return func(am *AksharamukhaManager) {
+		am.logFunc = fn
+	}
+}
+
+// Logs returns what the back container wrote to stdout and stderr since the given time.
+// A zero since returns the whole log.
+func (am *AksharamukhaManager) Logs(ctx context.Context, since time.Time) ([]LogLine, error) {
+	return am.logs(ctx, since, time.Time{})
+}
+
+// logs returns the lines written between since and until, a zero time leaving that end open
+func (am *AksharamukhaManager) logs(ctx context.Context, since, until time.Time) ([]LogLine, error) {
+	cli, err := am.docker.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	opts := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	}
+	if !since.IsZero() {
+		opts.Since = fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond())
+	}
+	if !until.IsZero() {
+		opts.Until = fmt.Sprintf("%d.%09d", until.Unix(), until.Nanosecond())
+	}
+
+	rc, err := cli.ContainerLogs(ctx, am.backContainer, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs of %s: %w", am.backContainer, err)
+	}
+	defer rc.Close()
+
+	var lines []LogLine
+	stdout := &lineWriter{fn: func(s string) { lines = append(lines, LogLine{Stream: "stdout", Text: s}) }}
+	stderr := &lineWriter{fn: func(s string) { lines = append(lines, LogLine{Stream: "stderr", Text: s}) }}
+	if _, err := stdcopy.StdCopy(stdout, stderr, rc); err != nil {
+		return nil, fmt.Errorf("failed to read logs of %s: %w", am.backContainer, err)
+	}
+	stdout.Flush()
+	stderr.Flush()
+	return lines, nil
+}
+
+// tracebackSlack widens the log window of a failed request, since docker log
+// timestamps and the local clock may disagree slightly
+const tracebackSlack = 250 * time.Millisecond
+
+// backendTraceback fetches the lines the backend logged while the failed request
+// was in flight and keeps the traceback belonging to it, see extractTraceback.
+func (am *AksharamukhaManager) backendTraceback(start, end time.Time, query string) []string {
+	if am.docker == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lines, err := am.logs(ctx, start.Add(-tracebackSlack), end.Add(tracebackSlack))
+	if err != nil {
+		return nil
+	}
+	return extractTraceback(lines, query)
+}
+
+// extractTraceback picks the Python traceback of the request whose encoded query
+// is given, found in the traceback itself or in the line logged just before it
+// (gunicorn's "Error handling request /api/public?…"). Failing that, a lone
+// traceback is assumed to be the request's; when several requests failed in the
+// same window and none can be matched, nothing is returned rather than another
+// request's traceback. Without any traceback, the stderr lines are kept.
+func extractTraceback(lines []LogLine, query string) (tb []string) {
+	// a traceback runs from its header through the indented frames to the
+	// first unindented line, the exception
+	type block struct {
+		start, end int
+		open       bool
+	}
+	var blocks []block
+	for i, line := range lines {
+		switch {
+		case strings.Contains(line.Text, "Traceback (most recent call last)"):
+			blocks = append(blocks, block{i, i + 1, true})
+		case len(blocks) > 0 && blocks[len(blocks)-1].open && line.Stream == "stderr":
+			b := &blocks[len(blocks)-1]
+			b.end = i + 1
+			b.open = strings.HasPrefix(line.Text, " ") || strings.HasPrefix(line.Text, "\t")
+		}
+	}
+
+	var chosen *block
+	for i, b := range blocks {
+		from := max(b.start-1, 0)
+		for _, line := range lines[from:b.end] {
+			if query != "" && strings.Contains(line.Text, query) {
+				chosen = &blocks[i]
+			}
+		}
+	}
+	if chosen == nil && len(blocks) == 1 {
+		chosen = &blocks[0]
+	}
+
+	switch {
+	case chosen != nil:
+		for _, line := range lines[chosen.start:chosen.end] {
+			tb = append(tb, line.Text)
+		}
+	case len(blocks) == 0:
+		for _, line := range lines {
+			if line.Stream == "stderr" {
+				tb = append(tb, line.Text)
+			}
+		}
+	}
+	if len(tb) > maxTracebackLines {
+		tb = tb[len(tb)-maxTracebackLines:]
+	}
+	return
+}
+
+// lineWriter splits what is written to it into lines and hands each one to fn
+type lineWriter struct {
+	fn  func(string)
+	buf []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := strings.IndexByte(string(w.buf), '\n')
+		if i < 0 {
+			break
+		}
+		w.fn(strings.TrimRight(string(w.buf[:i]), "\r"))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush emits a trailing line that has no newline
+func (w *lineWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.fn(string(w.buf))
+		w.buf = nil
+	}
+}
+
+// logTee forwards attached container output to the user's sink and callback
+// on top of the regular dockerutil log consumer (which also watches for the init message)
+type logTee struct {
+	*dockerutil.ContainerLogConsumer
+	sink io.Writer
+	fn   func(LogLine)
+	mu   sync.Mutex
+}
+
+func (t *logTee) Log(containerName, message string) {
+	t.ContainerLogConsumer.Log(containerName, message)
+	t.forward("stdout", message)
+}
+
+func (t *logTee) Err(containerName, message string) {
+	t.ContainerLogConsumer.Err(containerName, message)
+	t.forward("stderr", message)
+}
+
+func (t *logTee) forward(stream, message string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(message, "\n"), "\n") {
+		if t.sink != nil {
+			fmt.Fprintln(t.sink, line)
+		}
+		if t.fn != nil {
+			t.fn(LogLine{Stream: stream, Text: line})
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package aksharamukha
+
+import "context"
+
+// Request is a single conversion as seen by middleware
+type Request struct {
+	Text     string
+	From, To Script
+	Options  TranslitOptions
+}
+
+// Handler performs a conversion
+type Handler func(ctx context.Context, req Request) (string, error)
+
+// Middleware wraps a Handler. It may change the request before calling next,
+// change the response after, or return without calling next at all.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middleware around every conversion of the manager,
+// including each target of TranslitMulti. The first middleware is the outermost.
+func WithMiddleware(mw ...Middleware) ManagerOption {
+	return func(am *AksharamukhaManager) {
+		am.middleware = append(am.middleware, mw...)
+	}
+}
+
+// handler returns the manager's middleware chain around the backend call
+func (am *AksharamukhaManager) handler() Handler {
+	h := func(ctx context.Context, req Request) (string, error) {
+		if err := checkTarget(req.From, req.To, req.Options); err != nil {
+			return "", err
+		}
+		return am.query(ctx, req.Text, req.From, req.To, req.Options)
+	}
+	for i := len(am.middleware) - 1; i >= 0; i-- {
+		h = am.middleware[i](h)
+	}
+	return h
+}
@@ -0,0 +1,119 @@
+package aksharamukha
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxConcurrency bounds the number of simultaneous backend requests of a batch conversion
+var DefaultMaxConcurrency = 4
+
+// WithMaxConcurrency sets how many backend requests a batch conversion may run at once
+func WithMaxConcurrency(n int) ManagerOption {
+	return func(am *AksharamukhaManager) {
+		am.maxConcurrency = n
+	}
+}
+
+// MultiError collects the per-target failures of TranslitMulti
+type MultiError map[Script]error
+
+func (e MultiError) Error() string {
+	targets := make([]Script, 0, len(e))
+	for to := range e {
+		targets = append(targets, to)
+	}
+	slices.Sort(targets)
+
+	msgs := make([]string, len(targets))
+	for i, to := range targets {
+		msgs[i] = fmt.Sprintf("%s: %v", to, e[to])
+	}
+	return fmt.Sprintf("%d target(s) failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+func (e MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// TranslitMulti converts text to each of the targets concurrently. The input is
+// validated, an empty source detected (see TranslitOptions.SendDetectedSource)
+// and the container brought up once for all of them. Warnings of the targets are
+// handed to opts.OnWarning one at a time. The returned map holds every successful
+// conversion; if some targets failed, their errors are returned as a MultiError
+// alongside the partial results.
+func (am *AksharamukhaManager) TranslitMulti(ctx context.Context, text string, from Script, targets []Script, opts TranslitOptions) (map[Script]string, error) {
+	if err := am.begin(); err != nil {
+		return nil, err
+	}
+	defer am.end()
+
+	if err := checkSource(text, from); err != nil {
+		return nil, err
+	}
+	if err := am.ensureInit(ctx); err != nil {
+		return nil, err
+	}
+	if from == "" && opts.SendDetectedSource {
+		from = detectSource(text)
+	}
+	if onWarning := opts.OnWarning; onWarning != nil {
+		var warnMu sync.Mutex
+		opts.OnWarning = func(w Warning) {
+			warnMu.Lock()
+			defer warnMu.Unlock()
+			onWarning(w)
+		}
+	}
+
+	limit := am.maxConcurrency
+	if limit <= 0 {
+		limit = DefaultMaxConcurrency
+	}
+	sem := make(chan struct{}, limit)
+	handle := am.handler()
+
+	var (
+		wg      sync.WaitGroup
+		resMu   sync.Mutex
+		results = make(map[Script]string, len(targets))
+		failed  = make(MultiError)
+	)
+	for _, to := range slices.Compact(slices.Sorted(slices.Values(targets))) {
+		wg.Add(1)
+		go func(to Script) {
+			defer wg.Done()
+
+			var result string
+			var err error
+			select {
+			case sem <- struct{}{}:
+				result, err = handle(ctx, Request{Text: text, From: from, To: to, Options: opts})
+				<-sem
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+
+			resMu.Lock()
+			defer resMu.Unlock()
+			if err != nil {
+				failed[to] = err
+				return
+			}
+			results[to] = result
+		}(to)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return results, failed
+	}
+	return results, nil
+}
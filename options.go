@@ -0,0 +1,340 @@
+package aksharamukha
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PreOption is an Aksharamukha option applied to the input before conversion
+type PreOption string
+
+// PostOption is an Aksharamukha option applied to the output after conversion
+type PostOption string
+
+// Pre-options, applied to the source text. The catalog, options.txt, may hold
+// more than the constants here (see PreOptions), and the backend supports more
+// than the catalog: RegisterPreOption and RegisterPostOption add the missing ones.
+const (
+	PreRemoveSchwaHindi        PreOption = "RemoveSchwaHindi"
+	PreTamilTranscribe         PreOption = "TamilTranscribe"
+	PreTamilSubScript          PreOption = "TamilSubScript"
+	PreThaiPhonetic            PreOption = "ThaiPhonetic"
+	PreThaiSajjhayaOrthography PreOption = "ThaiSajjhayaOrthography"
+	PreLaoPhonetic             PreOption = "LaoPhonetic"
+)
+
+// Post-options, applied to the converted text, see PostOptions
+const (
+	PostRemoveSchwaHindi        PostOption = "RemoveSchwaHindi"
+	PostSchwaFinalGurmukhi      PostOption = "SchwaFinalGurmukhi"
+	PostSchwaFinalBengali       PostOption = "SchwaFinalBengali"
+	PostSchwaFinalGujarati      PostOption = "SchwaFinalGujarati"
+	PostThaiPhonetic            PostOption = "ThaiPhonetic"
+	PostThaiTranscription       PostOption = "ThaiTranscription"
+	PostThaiSajjhayaOrthography PostOption = "ThaiSajjhayaOrthography"
+	PostThaiSajjhayawithA       PostOption = "ThaiSajjhayawithA"
+	PostThaiNativeConsonants    PostOption = "ThaiNativeConsonants"
+	PostThaiVisargaSaraA        PostOption = "ThaiVisargaSaraA"
+	PostLaoPhonetic             PostOption = "LaoPhonetic"
+	PostLaoTranscription        PostOption = "LaoTranscription"
+	PostSinhalaPali             PostOption = "SinhalaPali"
+	PostSinhalaConjuncts        PostOption = "SinhalaConjuncts"
+	PostTamilSubScript          PostOption = "TamilSubScript"
+	PostTamilRemoveApostrophe   PostOption = "TamilRemoveApostrophe"
+	PostTamilRemoveNumbers      PostOption = "TamilRemoveNumbers"
+	PostMalayalamChillu         PostOption = "MalayalamChillu"
+	PostTeluguRemoveNukta       PostOption = "TeluguRemoveNukta"
+	PostTeluguRemoveAeAo        PostOption = "TeluguRemoveAeAo"
+	PostTeluguNakaraPollu       PostOption = "TeluguNakaraPollu"
+	PostKannadaNakaraPollu      PostOption = "KannadaNakaraPollu"
+	PostGurmukhiTippiBindu      PostOption = "GurmukhiTippiBindu"
+	PostGurmukhiYakaash         PostOption = "GurmukhiYakaash"
+	PostBengaliSwitchYaYYa      PostOption = "BengaliSwitchYaYYa"
+	PostOriyaVa                 PostOption = "OriyaVa"
+	PostRemoveDiacritics        PostOption = "RemoveDiacritics"
+	PostCapitalizeSentence      PostOption = "capitalizeSentence"
+	PostShowSchwaHindi          PostOption = "ShowSchwaHindi"
+	PostSchwaFinalWarangCiti    PostOption = "SchwaFinalWarangCiti"
+	PostTibetanRemoveVirama     PostOption = "TibetanRemoveVirama"
+	PostTibetanRemoveBa         PostOption = "TibetanRemoveBa"
+)
+
+// OptionInfo describes a pre- or post-option and where it applies
+type OptionInfo struct {
+	Name        string
+	Description string
+	// Sources restricts the option to these source scripts, nil means any
+	Sources []Script
+	// Targets restricts the option to these target scripts, nil means any
+	Targets []Script
+}
+
+// AppliesTo reports whether the option makes sense when converting from → to.
+// An empty from (auto-detected source) satisfies any source restriction.
+func (info OptionInfo) AppliesTo(from, to Script) bool {
+	if len(info.Sources) > 0 && from != "" && !slices.Contains(info.Sources, from) {
+		return false
+	}
+	if len(info.Targets) > 0 && !slices.Contains(info.Targets, to) {
+		return false
+	}
+	return true
+}
+
+// romanTargets are the romanization schemes, for options that only make sense on Latin output
+var romanTargets = scriptsWhere(func(info ScriptInfo) bool {
+	return info.Category.Has(CategoryRoman) && info.Script != IPA && info.Script != Type
+})
+
+// catalogMu guards preOptionCatalog and postOptionCatalog
+var catalogMu sync.RWMutex
+
+//go:embed options.txt
+var optionData string
+
+// preOptionCatalog and postOptionCatalog hold the embedded options.txt, then
+// whatever RegisterPreOption and RegisterPostOption add
+var preOptionCatalog, postOptionCatalog = func() (map[PreOption]OptionInfo, map[PostOption]OptionInfo) {
+	pre, post, err := parseOptionCatalog(optionData)
+	if err != nil {
+		panic("options.txt: " + err.Error())
+	}
+	return pre, post
+}()
+
+// parseOptionCatalog reads options.txt: comment lines start with #, every other
+// line holds the kind, name, sources, targets and description of an option
+func parseOptionCatalog(data string) (map[PreOption]OptionInfo, map[PostOption]OptionInfo, error) {
+	pre := make(map[PreOption]OptionInfo)
+	post := make(map[PostOption]OptionInfo)
+	for i, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 5)
+		if len(fields) < 4 {
+			return nil, nil, fmt.Errorf("line %d: want kind, name, sources, targets and description", i+1)
+		}
+		info := OptionInfo{Name: fields[1]}
+		if len(fields) == 5 {
+			info.Description = fields[4]
+		}
+		info.Sources = parseOptionScripts(fields[2])
+		info.Targets = parseOptionScripts(fields[3])
+		switch fields[0] {
+		case "pre":
+			pre[PreOption(info.Name)] = info
+		case "post":
+			post[PostOption(info.Name)] = info
+		default:
+			return nil, nil, fmt.Errorf("line %d: unknown option kind %q", i+1, fields[0])
+		}
+	}
+	return pre, post, nil
+}
+
+// parseOptionScripts reads the sources or targets field of options.txt. It runs
+// before the script registry is filled, the names are checked by TestOptionCatalog.
+func parseOptionScripts(field string) (scripts []Script) {
+	switch field {
+	case "*":
+		return nil
+	case "@roman":
+		return romanTargets
+	}
+	for _, name := range strings.Split(field, ",") {
+		scripts = append(scripts, Script(name))
+	}
+	return
+}
+
+// formatOptionScripts writes the sources or targets field of options.txt
+func formatOptionScripts(scripts []Script) string {
+	switch {
+	case scripts == nil:
+		return "*"
+	case slices.Equal(scripts, romanTargets):
+		return "@roman"
+	}
+	names := make([]string, len(scripts))
+	for i, s := range scripts {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ",")
+}
+
+// formatOptionCatalog writes a catalog in the format read by parseOptionCatalog
+func formatOptionCatalog(header string, pre []OptionInfo, post []OptionInfo) string {
+	var b strings.Builder
+	b.WriteString(header)
+	for _, group := range []struct {
+		kind  string
+		infos []OptionInfo
+	}{{"pre", pre}, {"post", post}} {
+		for _, info := range group.infos {
+			fmt.Fprintf(&b, "%s %s %s %s %s\n", group.kind, info.Name,
+				formatOptionScripts(info.Sources), formatOptionScripts(info.Targets), info.Description)
+		}
+	}
+	return b.String()
+}
+
+// RegisterPreOption adds or replaces a pre-option in the catalog, for options
+// the backend supports that this package does not know about yet
+func RegisterPreOption(name PreOption, info OptionInfo) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	info.Name = string(name)
+	preOptionCatalog[name] = info
+}
+
+// RegisterPostOption adds or replaces a post-option in the catalog
+func RegisterPostOption(name PostOption, info OptionInfo) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	info.Name = string(name)
+	postOptionCatalog[name] = info
+}
+
+// Info returns the catalog entry of the pre-option
+func (o PreOption) Info() (OptionInfo, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	info, ok := preOptionCatalog[o]
+	return info, ok
+}
+
+// Info returns the catalog entry of the post-option
+func (o PostOption) Info() (OptionInfo, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	info, ok := postOptionCatalog[o]
+	return info, ok
+}
+
+// PreOptions lists every known pre-option, sorted by name
+func PreOptions() []PreOption {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	opts := make([]PreOption, 0, len(preOptionCatalog))
+	for name := range preOptionCatalog {
+		opts = append(opts, name)
+	}
+	sort.Slice(opts, func(i, j int) bool { return opts[i] < opts[j] })
+	return opts
+}
+
+// PostOptions lists every known post-option, sorted by name
+func PostOptions() []PostOption {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	opts := make([]PostOption, 0, len(postOptionCatalog))
+	for name := range postOptionCatalog {
+		opts = append(opts, name)
+	}
+	sort.Slice(opts, func(i, j int) bool { return opts[i] < opts[j] })
+	return opts
+}
+
+// OptionsFor lists the pre- and post-options that apply when converting from → to
+func OptionsFor(from, to Script) (pre []PreOption, post []PostOption) {
+	for _, name := range PreOptions() {
+		if info, _ := name.Info(); info.AppliesTo(from, to) {
+			pre = append(pre, name)
+		}
+	}
+	for _, name := range PostOptions() {
+		if info, _ := name.Info(); info.AppliesTo(from, to) {
+			post = append(post, name)
+		}
+	}
+	return
+}
+
+// WithPreOptions returns a copy of opts with the given pre-options appended
+func (opts TranslitOptions) WithPreOptions(pre ...PreOption) TranslitOptions {
+	opts.PreOptions = slices.Clone(opts.PreOptions)
+	for _, o := range pre {
+		opts.PreOptions = append(opts.PreOptions, string(o))
+	}
+	return opts
+}
+
+// WithPostOptions returns a copy of opts with the given post-options appended
+func (opts TranslitOptions) WithPostOptions(post ...PostOption) TranslitOptions {
+	opts.PostOptions = slices.Clone(opts.PostOptions)
+	for _, o := range post {
+		opts.PostOptions = append(opts.PostOptions, string(o))
+	}
+	return opts
+}
+
+// OptionError reports a pre- or post-option that is unknown or does not fit the script pair
+type OptionError struct {
+	Option string
+	// Post is true for post-options, false for pre-options
+	Post     bool
+	From, To Script
+	Unknown  bool
+}
+
+func (e *OptionError) Error() string {
+	kind := "pre-option"
+	if e.Post {
+		kind = "post-option"
+	}
+	if e.Unknown {
+		return fmt.Sprintf("unknown %s %q", kind, e.Option)
+	}
+	from := string(e.From)
+	if from == "" {
+		from = "auto-detected source"
+	}
+	return fmt.Sprintf("%s %q does not apply to %s → %s", kind, e.Option, from, e.To)
+}
+
+// ValidateOptions checks the pre- and post-options of opts against the catalog
+// for the given script pair. All problems found are joined in the returned error.
+func ValidateOptions(from, to Script, opts TranslitOptions) error {
+	var errs []error
+	for _, e := range optionProblems(from, to, opts) {
+		errs = append(errs, e)
+	}
+	return errors.Join(errs...)
+}
+
+// validateOptions is ValidateOptions honoring opts.Lenient
+func validateOptions(from, to Script, opts TranslitOptions) error {
+	if !opts.Lenient {
+		return ValidateOptions(from, to, opts)
+	}
+	for _, e := range optionProblems(from, to, opts) {
+		kind := WarnInapplicableOption
+		if e.Unknown {
+			kind = WarnUnknownOption
+		}
+		opts.warn(Warning{Kind: kind, Message: e.Error()})
+	}
+	return nil
+}
+
+func optionProblems(from, to Script, opts TranslitOptions) (problems []*OptionError) {
+	for _, name := range opts.PreOptions {
+		info, known := PreOption(name).Info()
+		if !known || !info.AppliesTo(from, to) {
+			problems = append(problems, &OptionError{Option: name, From: from, To: to, Unknown: !known})
+		}
+	}
+	for _, name := range opts.PostOptions {
+		info, known := PostOption(name).Info()
+		if !known || !info.AppliesTo(from, to) {
+			problems = append(problems, &OptionError{Option: name, Post: true, From: from, To: to, Unknown: !known})
+		}
+	}
+	return
+}
@@ -0,0 +1,191 @@
+package aksharamukha
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// scriptAliases are the common names of scripts that differ from their Script value
+var scriptAliases = map[string]Script{
+	"latin":                 Latn,
+	"hindi":                 Devanagari,
+	"hindi-script":          Devanagari,
+	"nagari":                Devanagari,
+	"devnagari":             Devanagari,
+	"arabic":                Arab,
+	"persian":               ArabFa,
+	"farsi":                 ArabFa,
+	"judeo-arabic":          HebrAr,
+	"cyrillic":              RussianCyrillic,
+	"russian":               RussianCyrillic,
+	"ethiopic":              Ethi,
+	"geez":                  Ethi,
+	"punjabi":               Gurmukhi,
+	"odia":                  Oriya,
+	"sinhalese":             Sinhala,
+	"myanmar":               Burmese,
+	"lanna":                 TaiTham,
+	"ol-chiki":              Santali,
+	"meitei":                MeeteiMayek,
+	"kharosthi":             Kharoshthi,
+	"harvard-kyoto":         HK,
+	"iso-15919":             ISO,
+	"iso15919":              ISO,
+	"imperial-aramaic":      Armi,
+	"aramaic":               Armi,
+	"inscriptional-pahlavi": Phli,
+	"psalter-pahlavi":       Phlp,
+	"parthian":              Prti,
+	"phoenician":            Phnx,
+	"samaritan":             Samr,
+	"nabataean":             Nbat,
+	"palmyrene":             Palm,
+	"ugaritic":              Ugar,
+	"hatran":                Hatr,
+	"elymaic":               Elym,
+	"manichaean":            Mani,
+	"sogdian":               Sogd,
+	"old-sogdian":           Sogo,
+	"old-south-arabian":     Sarb,
+	"old-north-arabian":     Narb,
+}
+
+// iso15924Preferred picks the script an ISO 15924 code stands for when several
+// scripts share it and the first registered is not the obvious one
+var iso15924Preferred = map[string]Script{
+	"Beng": Bengali,
+	"Batk": BatakToba,
+	"Latn": Latn,
+}
+
+// scriptNames maps the normalized names, aliases and ISO 15924 codes to scripts
+var scriptNames = func() map[string]Script {
+	m := make(map[string]Script)
+	for _, info := range scriptTable {
+		if code := normalizeScriptName(info.ISO15924); code != "" {
+			if _, taken := m[code]; !taken {
+				m[code] = info.Script
+			}
+		}
+	}
+	for code, s := range iso15924Preferred {
+		m[normalizeScriptName(code)] = s
+	}
+	for alias, s := range scriptAliases {
+		m[normalizeScriptName(alias)] = s
+	}
+	// the names themselves come last so that no code or alias shadows them
+	for _, info := range scriptTable {
+		m[normalizeScriptName(string(info.Script))] = info.Script
+	}
+	return m
+}()
+
+// normalizeScriptName lowercases s and drops spaces, hyphens and underscores
+func normalizeScriptName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(s)))
+}
+
+// ParseScript resolves a script name in any capitalization, with or without
+// hyphens ("hebr-ar", "HebrAr"), an ISO 15924 code ("Deva") or a common alias
+// ("latin", "hindi-script"). On failure the error suggests the nearest names.
+func ParseScript(s string) (Script, error) {
+	if script, ok := scriptNames[normalizeScriptName(s)]; ok {
+		return script, nil
+	}
+	if suggestions := suggestScripts(s); len(suggestions) > 0 {
+		return "", fmt.Errorf("unknown script %q, did you mean %s?", s, strings.Join(suggestions, ", "))
+	}
+	return "", fmt.Errorf("unknown script %q", s)
+}
+
+// suggestScripts returns up to 3 script names or aliases close to s
+func suggestScripts(s string) []string {
+	key := normalizeScriptName(s)
+	if key == "" {
+		return nil
+	}
+	maxDist := max(2, len(key)/3)
+
+	type candidate struct {
+		name string
+		dist int
+	}
+	var candidates []candidate
+	seen := make(map[string]bool)
+	consider := func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		if d := levenshtein(key, normalizeScriptName(name)); d <= maxDist {
+			candidates = append(candidates, candidate{name, d})
+		}
+	}
+	for _, info := range scriptTable {
+		consider(string(info.Script))
+	}
+	for alias := range scriptAliases {
+		consider(alias)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].dist != candidates[j].dist {
+			return candidates[i].dist < candidates[j].dist
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	var names []string
+	for i := 0; i < len(candidates) && i < 3; i++ {
+		names = append(names, candidates[i].name)
+	}
+	return names
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s Script) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler with ParseScript, an empty
+// text leaves the script unset
+func (s *Script) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = ""
+		return nil
+	}
+	script, err := ParseScript(string(text))
+	if err != nil {
+		return err
+	}
+	*s = script
+	return nil
+}
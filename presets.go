@@ -0,0 +1,117 @@
+package aksharamukha
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// Preset is a named bundle of options for a common convention
+type Preset struct {
+	Name        string
+	Description string
+	Nativize    NativizeMode
+	PreOptions  []PreOption
+	PostOptions []PostOption
+	// Target is the scheme the preset is meant for, empty if it does not imply one
+	Target Script
+}
+
+var (
+	presetsMu sync.RWMutex
+	presets   = map[string]Preset{}
+)
+
+func init() {
+	for _, p := range []Preset{
+		{
+			Name:        "hindi-schwa-deletion",
+			Description: "Hindi romanized as pronounced, with the silent inherent vowels deleted",
+			PreOptions:  []PreOption{PreRemoveSchwaHindi},
+			Target:      ISO,
+		},
+		{
+			Name:        "pali-thai",
+			Description: "Pali in Thai script, keeping the Pali orthography",
+			Nativize:    NativizeOff,
+			Target:      Thai,
+		},
+		{
+			Name:        "pali-sinhala",
+			Description: "Pali in Sinhala script, following the conventions of Sinhala Pali editions",
+			Nativize:    NativizeOff,
+			PostOptions: []PostOption{PostSinhalaPali},
+			Target:      Sinhala,
+		},
+		{
+			Name:        "tamil-readable",
+			Description: "Tamil read phonetically and romanized without diacritics",
+			PreOptions:  []PreOption{PreTamilTranscribe},
+			Target:      RomanReadable,
+		},
+	} {
+		presets[p.Name] = p
+	}
+}
+
+// RegisterPreset adds a preset or replaces the one with the same name
+func RegisterPreset(p Preset) error {
+	if p.Name == "" {
+		return fmt.Errorf("preset name is empty")
+	}
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	presets[p.Name] = p
+	return nil
+}
+
+// LookupPreset returns the preset registered under name
+func LookupPreset(name string) (Preset, bool) {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	p, ok := presets[name]
+	return p, ok
+}
+
+// Presets lists the registered presets, sorted by name
+func Presets() []Preset {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	list := make([]Preset, 0, len(presets))
+	for _, p := range presets {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Apply returns a copy of opts with the preset's settings: Nativize is overwritten
+// unless the preset leaves it at default, options are appended unless already present.
+func (p Preset) Apply(opts TranslitOptions) TranslitOptions {
+	if p.Nativize != NativizeDefault {
+		opts.Nativize = p.Nativize
+	}
+	opts.PreOptions = slices.Clone(opts.PreOptions)
+	for _, o := range p.PreOptions {
+		if !slices.Contains(opts.PreOptions, string(o)) {
+			opts.PreOptions = append(opts.PreOptions, string(o))
+		}
+	}
+	opts.PostOptions = slices.Clone(opts.PostOptions)
+	for _, o := range p.PostOptions {
+		if !slices.Contains(opts.PostOptions, string(o)) {
+			opts.PostOptions = append(opts.PostOptions, string(o))
+		}
+	}
+	return opts
+}
+
+// WithPreset returns a copy of opts with the named preset applied
+func (opts TranslitOptions) WithPreset(name string) (TranslitOptions, error) {
+	p, ok := LookupPreset(name)
+	if !ok {
+		return opts, fmt.Errorf("unknown preset %q", name)
+	}
+	return p.Apply(opts), nil
+}
@@ -0,0 +1,100 @@
+package aksharamukha
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RomanProfile customizes the romanization of one language
+type RomanProfile struct {
+	// Scheme overrides Script2RomanScheme for the language, empty keeps it
+	Scheme Script
+	// Preset names a preset whose options are applied, empty for none
+	Preset string
+	// PostProcess runs on the romanized output, may be nil
+	PostProcess func(string) string
+	// Styles overrides the scheme used for a RomanStyle, see RomanStyled
+	Styles map[RomanStyle]Script
+}
+
+var (
+	romanProfilesMu sync.RWMutex
+	// romanProfiles is keyed by ISO 639-3 code
+	romanProfiles = map[string]RomanProfile{
+		// Hindi drops most silent inherent vowels, Sanskrit keeps them all
+		"hin": {Preset: "hindi-schwa-deletion"},
+		"san": {Scheme: ISO},
+	}
+)
+
+// SetRomanProfile installs the profile used by every manager for the language,
+// unless a manager has its own (see WithRomanProfile)
+func SetRomanProfile(languageCode string, p RomanProfile) error {
+	stdLang, ok := IsValidISO639(languageCode)
+	if !ok {
+		return fmt.Errorf("\"%s\" isn't a ISO-639 language code", languageCode)
+	}
+	romanProfilesMu.Lock()
+	defer romanProfilesMu.Unlock()
+	romanProfiles[stdLang] = p
+	return nil
+}
+
+// RemoveRomanProfile drops the global profile of the language, which is then
+// romanized with the plain Script2RomanScheme mapping
+func RemoveRomanProfile(languageCode string) {
+	stdLang, ok := IsValidISO639(languageCode)
+	if !ok {
+		return
+	}
+	romanProfilesMu.Lock()
+	defer romanProfilesMu.Unlock()
+	delete(romanProfiles, stdLang)
+}
+
+// RomanProfileFor returns the global profile of the language
+func RomanProfileFor(languageCode string) (RomanProfile, bool) {
+	stdLang, ok := IsValidISO639(languageCode)
+	if !ok {
+		return RomanProfile{}, false
+	}
+	romanProfilesMu.RLock()
+	defer romanProfilesMu.RUnlock()
+	p, ok := romanProfiles[stdLang]
+	return p, ok
+}
+
+// WithRomanProfile sets a profile for the language on this manager only, taking
+// precedence over the global one. Invalid language codes are ignored.
+func WithRomanProfile(languageCode string, p RomanProfile) ManagerOption {
+	return func(am *AksharamukhaManager) {
+		stdLang, ok := IsValidISO639(languageCode)
+		if !ok {
+			return
+		}
+		if am.romanProfiles == nil {
+			am.romanProfiles = make(map[string]RomanProfile)
+		}
+		am.romanProfiles[stdLang] = p
+	}
+}
+
+// profileSource is implemented by transliterators carrying their own profiles
+type profileSource interface {
+	romanProfile(stdLang string) (RomanProfile, bool)
+}
+
+func (am *AksharamukhaManager) romanProfile(stdLang string) (RomanProfile, bool) {
+	if p, ok := am.romanProfiles[stdLang]; ok {
+		return p, true
+	}
+	return RomanProfileFor(stdLang)
+}
+
+// profileFor resolves the profile of a language for t, falling back to the global registry
+func profileFor(t Transliterator, stdLang string) (RomanProfile, bool) {
+	if src, ok := t.(profileSource); ok {
+		return src.romanProfile(stdLang)
+	}
+	return RomanProfileFor(stdLang)
+}
@@ -0,0 +1,354 @@
+package aksharamukha
+
+import "fmt"
+
+// ScriptCategory classifies a script, categories combine (e.g. Brahmi is
+// CategoryBrahmic|CategoryHistoric)
+type ScriptCategory uint8
+
+const (
+	// CategoryBrahmic covers the abugidas descended from Brahmi
+	CategoryBrahmic ScriptCategory = 1 << iota
+	// CategorySemitic covers the Semitic abjads and their descendants
+	CategorySemitic
+	// CategoryRoman covers the romanization schemes
+	CategoryRoman
+	// CategoryHistoric marks scripts no longer in everyday use
+	CategoryHistoric
+	// CategoryOther covers the scripts that fit none of the above (Cyrillic, kana, Ethiopic…)
+	CategoryOther
+)
+
+// Has reports whether c includes every category of other
+func (c ScriptCategory) Has(other ScriptCategory) bool {
+	return c&other == other
+}
+
+func (c ScriptCategory) String() string {
+	names := []string{"brahmic", "semitic", "roman", "historic", "other"}
+	s := ""
+	for i, name := range names {
+		if c&(1<<i) != 0 {
+			if s != "" {
+				s += "|"
+			}
+			s += name
+		}
+	}
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// Direction is the writing direction of a script
+type Direction int
+
+const (
+	LeftToRight Direction = iota
+	RightToLeft
+	TopToBottom
+)
+
+func (d Direction) String() string {
+	switch d {
+	case RightToLeft:
+		return "rtl"
+	case TopToBottom:
+		return "ttb"
+	}
+	return "ltr"
+}
+
+// SupportLevel tells how completely the backend handles a script
+type SupportLevel int
+
+const (
+	SupportFull SupportLevel = iota
+	// SupportIndicSubset: only the Indic/Pali subset of the script is handled
+	SupportIndicSubset
+)
+
+func (l SupportLevel) String() string {
+	if l == SupportIndicSubset {
+		return "indic-subset"
+	}
+	return "full"
+}
+
+// UnicodeRange is an inclusive range of code points
+type UnicodeRange struct {
+	Lo, Hi rune
+}
+
+// Contains reports whether r is in the range
+func (ur UnicodeRange) Contains(r rune) bool {
+	return r >= ur.Lo && r <= ur.Hi
+}
+
+// inRanges reports whether r is in any of ranges
+func inRanges(r rune, ranges []UnicodeRange) bool {
+	for _, ur := range ranges {
+		if ur.Contains(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScriptInfo is the registry record of a script
+type ScriptInfo struct {
+	Script Script
+	// ISO15924 is the four-letter code of the script, empty for scripts that have
+	// none or no Unicode block to segment by (Ariyaka, Pallava, Ranjana, Vatteluttu
+	// are rendered with fonts over other blocks)
+	ISO15924  string
+	Ranges    []UnicodeRange
+	Direction Direction
+	Category  ScriptCategory
+	Support   SupportLevel
+}
+
+// Shared Unicode blocks
+var (
+	latinRanges = []UnicodeRange{
+		{0x0041, 0x005A}, {0x0061, 0x007A}, {0x00C0, 0x024F}, {0x0300, 0x036F}, {0x1E00, 0x1EFF},
+	}
+	arabicRanges     = []UnicodeRange{{0x0600, 0x06FF}, {0x0750, 0x077F}, {0x08A0, 0x08FF}, {0xFB50, 0xFDFF}, {0xFE70, 0xFEFF}}
+	hebrewRanges     = []UnicodeRange{{0x0590, 0x05FF}, {0xFB1D, 0xFB4F}}
+	syriacRanges     = []UnicodeRange{{0x0700, 0x074F}, {0x0860, 0x086F}}
+	devanagariRanges = []UnicodeRange{{0x0900, 0x097F}, {0xA8E0, 0xA8FF}, {0x1CD0, 0x1CFF}}
+	bengaliRanges    = []UnicodeRange{{0x0980, 0x09FF}}
+	tamilRanges      = []UnicodeRange{{0x0B80, 0x0BFF}, {0x11FC0, 0x11FFF}}
+	thaiRanges       = []UnicodeRange{{0x0E00, 0x0E7F}}
+	laoRanges        = []UnicodeRange{{0x0E80, 0x0EFF}}
+	myanmarRanges    = []UnicodeRange{{0x1000, 0x109F}, {0xA9E0, 0xA9FF}, {0xAA60, 0xAA7F}}
+	taiThamRanges    = []UnicodeRange{{0x1A20, 0x1AAF}}
+	batakRanges      = []UnicodeRange{{0x1BC0, 0x1BFF}}
+	brahmiRanges     = []UnicodeRange{{0x11000, 0x1107F}}
+	granthaRanges    = []UnicodeRange{{0x11300, 0x1137F}}
+)
+
+func rng(lo, hi rune) []UnicodeRange {
+	return []UnicodeRange{{lo, hi}}
+}
+
+// scriptTable lists every script the backend knows, in the order of the Script constants
+var scriptTable = []ScriptInfo{
+	{Ahom, "Ahom", rng(0x11700, 0x1174F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Arab, "Arab", arabicRanges, RightToLeft, CategorySemitic, SupportFull},
+	{Ariyaka, "", nil, LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Assamese, "Beng", bengaliRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Avestan, "Avst", rng(0x10B00, 0x10B3F), RightToLeft, CategoryOther | CategoryHistoric, SupportFull},
+	{Balinese, "Bali", rng(0x1B00, 0x1B7F), LeftToRight, CategoryBrahmic, SupportFull},
+	{BatakKaro, "Batk", batakRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{BatakManda, "Batk", batakRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{BatakPakpak, "Batk", batakRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{BatakSima, "Batk", batakRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{BatakToba, "Batk", batakRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Bengali, "Beng", bengaliRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Bhaiksuki, "Bhks", rng(0x11C00, 0x11C6F), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Brahmi, "Brah", brahmiRanges, LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Buginese, "Bugi", rng(0x1A00, 0x1A1F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Buhid, "Buhd", rng(0x1740, 0x175F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Burmese, "Mymr", myanmarRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Chakma, "Cakm", rng(0x11100, 0x1114F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Cham, "Cham", rng(0xAA00, 0xAA5F), LeftToRight, CategoryBrahmic, SupportFull},
+	{RussianCyrillic, "Cyrl", rng(0x0400, 0x052F), LeftToRight, CategoryOther, SupportFull},
+	{Devanagari, "Deva", devanagariRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Dogra, "Dogr", rng(0x11800, 0x1184F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Elym, "Elym", rng(0x10FE0, 0x10FFF), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Ethi, "Ethi", []UnicodeRange{{0x1200, 0x139F}, {0x2D80, 0x2DDF}}, LeftToRight, CategoryOther, SupportFull},
+	{GunjalaGondi, "Gong", rng(0x11D60, 0x11DAF), LeftToRight, CategoryBrahmic, SupportFull},
+	{MasaramGondi, "Gonm", rng(0x11D00, 0x11D5F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Grantha, "Gran", granthaRanges, LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{GranthaPandya, "Gran", granthaRanges, LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Gujarati, "Gujr", rng(0x0A80, 0x0AFF), LeftToRight, CategoryBrahmic, SupportFull},
+	{Hanunoo, "Hano", rng(0x1720, 0x173F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Hatr, "Hatr", rng(0x108E0, 0x108FF), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Hebrew, "Hebr", hebrewRanges, RightToLeft, CategorySemitic, SupportFull},
+	{HebrAr, "Hebr", hebrewRanges, RightToLeft, CategorySemitic, SupportFull},
+	{Armi, "Armi", rng(0x10840, 0x1085F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Phli, "Phli", rng(0x10B60, 0x10B7F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Prti, "Prti", rng(0x10B40, 0x10B5F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Hiragana, "Hira", rng(0x3040, 0x309F), LeftToRight, CategoryOther, SupportFull},
+	{Katakana, "Kana", []UnicodeRange{{0x30A0, 0x30FF}, {0x31F0, 0x31FF}}, LeftToRight, CategoryOther, SupportFull},
+	{Javanese, "Java", rng(0xA980, 0xA9DF), LeftToRight, CategoryBrahmic, SupportFull},
+	{Kaithi, "Kthi", rng(0x11080, 0x110CF), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Kannada, "Knda", rng(0x0C80, 0x0CFF), LeftToRight, CategoryBrahmic, SupportFull},
+	{Kawi, "Kawi", rng(0x11F00, 0x11F5F), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{KhamtiShan, "Mymr", myanmarRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Kharoshthi, "Khar", rng(0x10A00, 0x10A5F), RightToLeft, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Khmer, "Khmr", []UnicodeRange{{0x1780, 0x17FF}, {0x19E0, 0x19FF}}, LeftToRight, CategoryBrahmic, SupportFull},
+	{Khojki, "Khoj", rng(0x11200, 0x1124F), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{KhomThai, "Thai", thaiRanges, LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Khudawadi, "Sind", rng(0x112B0, 0x112FF), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Lao, "Laoo", laoRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{LaoPali, "Laoo", laoRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Lepcha, "Lepc", rng(0x1C00, 0x1C4F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Limbu, "Limb", rng(0x1900, 0x194F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Mahajani, "Mahj", rng(0x11150, 0x1117F), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Makasar, "Maka", rng(0x11EE0, 0x11EFF), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Malayalam, "Mlym", rng(0x0D00, 0x0D7F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Mani, "Mani", rng(0x10AC0, 0x10AFF), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Marchen, "Marc", rng(0x11C70, 0x11CBF), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{MeeteiMayek, "Mtei", []UnicodeRange{{0xAAE0, 0xAAFF}, {0xABC0, 0xABFF}}, LeftToRight, CategoryBrahmic, SupportFull},
+	{Modi, "Modi", rng(0x11600, 0x1165F), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Mon, "Mymr", myanmarRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Mongolian, "Mong", []UnicodeRange{{0x1800, 0x18AF}, {0x11660, 0x1167F}}, TopToBottom, CategoryOther, SupportFull},
+	{Mro, "Mroo", rng(0x16A40, 0x16A6F), LeftToRight, CategoryOther, SupportFull},
+	{Multani, "Mult", rng(0x11280, 0x112AF), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Nbat, "Nbat", rng(0x10880, 0x108AF), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Nandinagari, "Nand", rng(0x119A0, 0x119FF), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Newa, "Newa", rng(0x11400, 0x1147F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Narb, "Narb", rng(0x10A80, 0x10A9F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{OldPersian, "Xpeo", rng(0x103A0, 0x103DF), LeftToRight, CategoryOther | CategoryHistoric, SupportFull},
+	{Sogo, "Sogo", rng(0x10F00, 0x10F2F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Sarb, "Sarb", rng(0x10A60, 0x10A7F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Oriya, "Orya", rng(0x0B00, 0x0B7F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Pallava, "", nil, LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Palm, "Palm", rng(0x10860, 0x1087F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{ArabFa, "Arab", arabicRanges, RightToLeft, CategorySemitic, SupportFull},
+	{PhagsPa, "Phag", rng(0xA840, 0xA87F), TopToBottom, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Phnx, "Phnx", rng(0x10900, 0x1091F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Phlp, "Phlp", rng(0x10B80, 0x10BAF), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Gurmukhi, "Guru", rng(0x0A00, 0x0A7F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Ranjana, "", nil, LeftToRight, CategoryBrahmic, SupportFull},
+	{Rejang, "Rjng", rng(0xA930, 0xA95F), LeftToRight, CategoryBrahmic, SupportFull},
+	{HanifiRohingya, "Rohg", rng(0x10D00, 0x10D3F), RightToLeft, CategoryOther, SupportFull},
+	{BarahaNorth, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{BarahaSouth, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{RomanColloquial, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{PersianDMG, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{HK, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{IAST, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{IASTPali, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{IPA, "Latn", append([]UnicodeRange{{0x0250, 0x02AF}}, latinRanges...), LeftToRight, CategoryRoman, SupportFull},
+	{ISO, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{ISOPali, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{ISO233, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{ISO259, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{Itrans, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{IASTLOC, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{RomanReadable, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{HebrewSBL, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{SLP1, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{Type, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{Latn, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{Titus, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{Velthuis, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{WX, "Latn", latinRanges, LeftToRight, CategoryRoman, SupportFull},
+	{Samr, "Samr", rng(0x0800, 0x083F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{Santali, "Olck", rng(0x1C50, 0x1C7F), LeftToRight, CategoryOther, SupportFull},
+	{Saurashtra, "Saur", rng(0xA880, 0xA8DF), LeftToRight, CategoryBrahmic, SupportFull},
+	{Shahmukhi, "Arab", arabicRanges, RightToLeft, CategorySemitic, SupportFull},
+	{Shan, "Mymr", myanmarRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Sharada, "Shrd", rng(0x11180, 0x111DF), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Siddham, "Sidd", rng(0x11580, 0x115FF), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Sinhala, "Sinh", []UnicodeRange{{0x0D80, 0x0DFF}, {0x111E0, 0x111FF}}, LeftToRight, CategoryBrahmic, SupportFull},
+	{Sogd, "Sogd", rng(0x10F30, 0x10F6F), RightToLeft, CategorySemitic | CategoryHistoric, SupportFull},
+	{SoraSompeng, "Sora", rng(0x110D0, 0x110FF), LeftToRight, CategoryOther, SupportFull},
+	{Soyombo, "Soyo", rng(0x11A50, 0x11AAF), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Sundanese, "Sund", []UnicodeRange{{0x1B80, 0x1BBF}, {0x1CC0, 0x1CCF}}, LeftToRight, CategoryBrahmic, SupportFull},
+	{SylotiNagri, "Sylo", rng(0xA800, 0xA82F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Syrn, "Syrn", syriacRanges, RightToLeft, CategorySemitic, SupportFull},
+	{Syre, "Syre", syriacRanges, RightToLeft, CategorySemitic, SupportFull},
+	{Syrj, "Syrj", syriacRanges, RightToLeft, CategorySemitic, SupportFull},
+	{Tagalog, "Tglg", rng(0x1700, 0x171F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Tagbanwa, "Tagb", rng(0x1760, 0x177F), LeftToRight, CategoryBrahmic, SupportFull},
+	{TaiLaing, "Mymr", myanmarRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Takri, "Takr", rng(0x11680, 0x116CF), LeftToRight, CategoryBrahmic, SupportFull},
+	{Tamil, "Taml", tamilRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{TamilExtended, "Taml", tamilRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{TamilBrahmi, "Brah", brahmiRanges, LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Telugu, "Telu", rng(0x0C00, 0x0C7F), LeftToRight, CategoryBrahmic, SupportFull},
+	{Thaana, "Thaa", rng(0x0780, 0x07BF), RightToLeft, CategoryOther, SupportFull},
+	{Thai, "Thai", thaiRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{TaiTham, "Lana", taiThamRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{LaoTham, "Lana", taiThamRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{KhuenTham, "Lana", taiThamRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{LueTham, "Lana", taiThamRanges, LeftToRight, CategoryBrahmic, SupportFull},
+	{Tibetan, "Tibt", rng(0x0F00, 0x0FFF), LeftToRight, CategoryBrahmic, SupportFull},
+	{Tirhuta, "Tirh", rng(0x11480, 0x114DF), LeftToRight, CategoryBrahmic, SupportFull},
+	{Ugar, "Ugar", rng(0x10380, 0x1039F), LeftToRight, CategorySemitic | CategoryHistoric, SupportFull},
+	{Urdu, "Arab", arabicRanges, RightToLeft, CategorySemitic, SupportFull},
+	{Vatteluttu, "", nil, LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+	{Wancho, "Wcho", rng(0x1E2C0, 0x1E2FF), LeftToRight, CategoryOther, SupportFull},
+	{WarangCiti, "Wara", rng(0x118A0, 0x118FF), LeftToRight, CategoryOther, SupportFull},
+	{ZanabazarSquare, "Zanb", rng(0x11A00, 0x11A4F), LeftToRight, CategoryBrahmic | CategoryHistoric, SupportFull},
+}
+
+var scriptRegistry = make(map[Script]*ScriptInfo, len(scriptTable))
+
+func init() {
+	for i := range scriptTable {
+		info := &scriptTable[i]
+		if _, dup := scriptRegistry[info.Script]; dup {
+			panic(fmt.Sprintf("script %s registered twice", info.Script))
+		}
+		scriptRegistry[info.Script] = info
+	}
+	for _, name := range indicSubset {
+		if info, ok := scriptRegistry[Script(name)]; ok {
+			info.Support = SupportIndicSubset
+		}
+	}
+}
+
+// Scripts lists every registered script
+func Scripts() []Script {
+	list := make([]Script, len(scriptTable))
+	for i, info := range scriptTable {
+		list[i] = info.Script
+	}
+	return list
+}
+
+// Info returns the registry record of the script
+func (s Script) Info() (ScriptInfo, bool) {
+	info, ok := scriptRegistry[s]
+	if !ok {
+		return ScriptInfo{}, false
+	}
+	return *info, true
+}
+
+// IsRoman reports whether the script is a romanization scheme
+func (s Script) IsRoman() bool {
+	info, ok := scriptRegistry[s]
+	return ok && info.Category.Has(CategoryRoman)
+}
+
+// Direction returns the writing direction of the script, LeftToRight if unknown
+func (s Script) Direction() Direction {
+	if info, ok := scriptRegistry[s]; ok {
+		return info.Direction
+	}
+	return LeftToRight
+}
+
+// Category returns the categories of the script, zero if unknown
+func (s Script) Category() ScriptCategory {
+	if info, ok := scriptRegistry[s]; ok {
+		return info.Category
+	}
+	return 0
+}
+
+// ISO15924 returns the ISO 15924 code of the script, empty if it has none
+func (s Script) ISO15924() string {
+	if info, ok := scriptRegistry[s]; ok {
+		return info.ISO15924
+	}
+	return ""
+}
+
+// scriptsWhere lists the registered scripts matching keep
+func scriptsWhere(keep func(ScriptInfo) bool) (list []Script) {
+	for _, info := range scriptTable {
+		if keep(info) {
+			list = append(list, info.Script)
+		}
+	}
+	return
+}
@@ -0,0 +1,148 @@
+package aksharamukha
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// TranslitResult is a conversion along with what is known about how it was made
+type TranslitResult struct {
+	Text string
+	// Source is the script converted from: the requested one, or if the request
+	// left it empty, the one picked by DetectScript, which the backend detects
+	// on its own unless Options.SendDetectedSource is set. It is empty for Latin
+	// text and for ties DetectScript cannot settle.
+	Source Script
+	Target Script
+	// Warnings holds every warning of the conversion, including the suppressed ones
+	Warnings []Warning
+	// Duration covers the whole conversion, middleware and rules included
+	Duration time.Duration
+	// Cached is set when a middleware answered from a cache, see MarkCached
+	Cached bool
+	// BackendVersion identifies the backend image as looked up by the last Init,
+	// empty if it was not or could not be (see AksharamukhaManager.BackendVersion)
+	BackendVersion string
+}
+
+type resultMetaKey struct{}
+
+// resultMeta collects what middleware reports about the conversion in flight
+type resultMeta struct {
+	cached atomic.Bool
+}
+
+// MarkCached is called by a caching middleware answering without the backend,
+// so that TranslitDetailed reports the result as cached
+func MarkCached(ctx context.Context) {
+	if meta, ok := ctx.Value(resultMetaKey{}).(*resultMeta); ok {
+		meta.cached.Store(true)
+	}
+}
+
+// TranslitDetailedWithContext converts req using the default manager
+func TranslitDetailedWithContext(ctx context.Context, req Request) (*TranslitResult, error) {
+	mgr, err := getOrCreateDefaultManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mgr.TranslitDetailed(ctx, req)
+}
+
+// TranslitDetailed converts req and reports the detected source script, the
+// warnings, the timing, whether a cache answered and the backend version
+func (am *AksharamukhaManager) TranslitDetailed(ctx context.Context, req Request) (*TranslitResult, error) {
+	if err := am.begin(); err != nil {
+		return nil, err
+	}
+	defer am.end()
+
+	if err := checkSource(req.Text, req.From); err != nil {
+		return nil, err
+	}
+
+	if err := am.ensureInit(ctx); err != nil {
+		return nil, err
+	}
+
+	res := &TranslitResult{Source: req.From, Target: req.To}
+	if req.From == "" {
+		res.Source = detectSource(req.Text)
+		if req.Options.SendDetectedSource {
+			req.From = res.Source
+		}
+	}
+
+	onWarning := req.Options.OnWarning
+	suppressed := req.Options.SuppressWarnings
+	req.Options.SuppressWarnings = nil
+	req.Options.OnWarning = func(w Warning) {
+		res.Warnings = append(res.Warnings, w)
+		if onWarning != nil && !slices.Contains(suppressed, w.Kind) {
+			onWarning(w)
+		}
+	}
+
+	meta := &resultMeta{}
+	start := time.Now()
+	text, err := am.handler()(context.WithValue(ctx, resultMetaKey{}, meta), req)
+	res.Duration = time.Since(start)
+	if err != nil {
+		return nil, err
+	}
+	res.Text = text
+	res.Cached = meta.cached.Load()
+	res.BackendVersion = am.cachedBackendVersion()
+	return res, nil
+}
+
+// BackendVersion identifies the running backend image by its version label, or
+// failing that by its image ID. It is empty if the container cannot be inspected.
+// The version is looked up by Init, or by the first call for a manager used
+// without it, and kept until the next Init even if the lookup failed.
+func (am *AksharamukhaManager) BackendVersion(ctx context.Context) string {
+	am.initMu.Lock()
+	version, resolved := am.backendVersion, am.versionResolved
+	am.initMu.Unlock()
+	if resolved {
+		return version
+	}
+
+	version = am.inspectBackendVersion(ctx)
+	am.initMu.Lock()
+	am.backendVersion, am.versionResolved = version, true
+	am.initMu.Unlock()
+	return version
+}
+
+// cachedBackendVersion is BackendVersion without the lookup, for the conversion path
+func (am *AksharamukhaManager) cachedBackendVersion() string {
+	am.initMu.Lock()
+	defer am.initMu.Unlock()
+	return am.backendVersion
+}
+
+func (am *AksharamukhaManager) inspectBackendVersion(ctx context.Context) string {
+	if am.docker == nil {
+		return ""
+	}
+	cli, err := am.docker.GetClient()
+	if err != nil {
+		return ""
+	}
+	defer cli.Close()
+
+	info, err := cli.ContainerInspect(ctx, am.backContainer)
+	if err != nil {
+		return ""
+	}
+	version := info.Image
+	if info.Config != nil {
+		if label := info.Config.Labels["org.opencontainers.image.version"]; label != "" {
+			version = label
+		}
+	}
+	return version
+}
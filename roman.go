@@ -0,0 +1,153 @@
+package aksharamukha
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RomanResult is a romanization along with the source scripts it was read in
+type RomanResult struct {
+	Text string
+	// Scripts lists the source scripts used, in order of first appearance
+	Scripts []Script
+}
+
+// RomanDetailed romanizes text of the given language in the requested style and
+// reports the source scripts used.
+//
+// Unless the language tag names a script (e.g. "pa-Arab"), the text is checked
+// for each of the language's scripts listed in Lang2Scripts: text in a single
+// one is converted from it, text mixing several (e.g. Hiragana and Katakana) is
+// converted span by span, each from its own script, and whatever is written in
+// none of them is left as is. Text in none of them is read in the primary script.
+func RomanDetailed(ctx context.Context, text, languageCode string, style RomanStyle, opts TranslitOptions) (*RomanResult, error) {
+	mgr, err := getOrCreateDefaultManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return romanDetailed(ctx, mgr, text, languageCode, style, opts)
+}
+
+// RomanDetailed romanizes text in the requested style using this manager's
+// profiles and reports the source scripts used
+func (am *AksharamukhaManager) RomanDetailed(ctx context.Context, text, languageCode string, style RomanStyle, opts TranslitOptions) (*RomanResult, error) {
+	return romanDetailed(ctx, am, text, languageCode, style, opts)
+}
+
+func romanDetailed(ctx context.Context, t Transliterator, text, languageCode string, style RomanStyle, opts TranslitOptions) (*RomanResult, error) {
+	stdLang, _, err := parseLangTag(languageCode)
+	if err != nil {
+		return nil, err
+	}
+	spans, err := romanSpans(text, languageCode)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, _ := profileFor(t, stdLang)
+	if opts.Language == "" {
+		opts.Language = stdLang
+	}
+	if profile.Preset != "" {
+		if opts, err = opts.WithPreset(profile.Preset); err != nil {
+			return nil, fmt.Errorf("romanization profile of %s: %w", stdLang, err)
+		}
+	}
+
+	res := &RomanResult{}
+	var b strings.Builder
+	for _, span := range spans {
+		if span.source == "" {
+			b.WriteString(span.text)
+			continue
+		}
+		academic, err := romanScheme(span.source, profile)
+		if err != nil {
+			return nil, err
+		}
+		scheme, post := styleTarget(style, span.source, academic, profile, opts)
+
+		out, err := t.Translit(ctx, span.text, span.source, scheme, opts.WithPostOptions(post...))
+		if err != nil {
+			return nil, fmt.Errorf("romanization failed: %w", err)
+		}
+		b.WriteString(out)
+		if !slices.Contains(res.Scripts, span.source) {
+			res.Scripts = append(res.Scripts, span.source)
+		}
+	}
+
+	res.Text = b.String()
+	if profile.PostProcess != nil {
+		res.Text = profile.PostProcess(res.Text)
+	}
+	return res, nil
+}
+
+// romanSpan is a part of the text to romanize from source, left as is if source is empty
+type romanSpan struct {
+	text   string
+	source Script
+}
+
+// romanSpans splits text among the scripts of the language, see RomanDetailed
+func romanSpans(text, languageCode string) ([]romanSpan, error) {
+	primary, err := DefaultScriptFor(languageCode)
+	if err != nil {
+		return nil, err
+	}
+	stdLang, subtag, _ := parseLangTag(languageCode)
+	candidates := Lang2Scripts[stdLang]
+	if subtag != "" || len(candidates) < 2 {
+		return []romanSpan{{text, primary}}, nil
+	}
+
+	var spans []romanSpan
+	var found []Script
+	for _, seg := range SegmentByScript(text) {
+		source := candidateScript(seg, candidates)
+		if source != "" && !slices.Contains(found, source) {
+			found = append(found, source)
+		}
+		if n := len(spans); n > 0 && spans[n-1].source == source {
+			spans[n-1].text += seg.Text
+			continue
+		}
+		spans = append(spans, romanSpan{seg.Text, source})
+	}
+
+	switch len(found) {
+	case 0:
+		return []romanSpan{{text, primary}}, nil
+	case 1:
+		return []romanSpan{{text, found[0]}}, nil
+	}
+	return spans, nil
+}
+
+// candidateScript maps the script of a segment onto the language's script it
+// stands for: itself if listed and detection settled on it, else the first listed
+// script sharing its Unicode block (Urdu-looking text is Shahmukhi for Punjabi,
+// Syriac that no letter ties to a variant is the language's first Syriac script).
+// Empty if there is none.
+func candidateScript(seg Segment, candidates []string) Script {
+	s := seg.Script
+	if s == "" {
+		return ""
+	}
+	if slices.Contains(candidates, string(s)) && detectSource(seg.Text) == s {
+		return s
+	}
+	group, ok := groupIndex[s]
+	if !ok {
+		return ""
+	}
+	for _, c := range candidates {
+		if g, ok := groupIndex[Script(c)]; ok && g == group {
+			return Script(c)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,189 @@
+package aksharamukha
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rule is a rewrite applied to the backend output. Rules load from JSON files
+// holding an array of rules, for example:
+//
+//	[
+//	  {"name": "anusvara-dot-above", "to": "IAST", "match": "ṃ", "replace": "ṁ"},
+//	  {"name": "final-schwa", "from": "Devanagari", "lang": "hin", "regex": true, "match": "a(\\s|$)", "replace": "$1"}
+//	]
+type Rule struct {
+	Name string `json:"name"`
+	// From, To and Lang scope the rule, empty means any
+	From Script `json:"from,omitempty"`
+	To   Script `json:"to,omitempty"`
+	// Lang is an ISO 639 code matched against TranslitOptions.Language
+	Lang string `json:"lang,omitempty"`
+	// Match is a literal string, or a regular expression if Regex is set
+	Match   string `json:"match"`
+	Replace string `json:"replace"`
+	Regex   bool   `json:"regex,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// RuleTrace describes a rule that changed the output
+type RuleTrace struct {
+	Rule          string
+	Before, After string
+}
+
+// RuleSet is an ordered list of rules, applied in the order they were loaded
+type RuleSet struct {
+	rules []Rule
+}
+
+// NewRuleSet validates and compiles rules
+func NewRuleSet(rules ...Rule) (*RuleSet, error) {
+	rs := &RuleSet{}
+	for i, r := range rules {
+		if err := r.compile(); err != nil {
+			name := r.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("rule %s: %w", name, err)
+		}
+		rs.rules = append(rs.rules, r)
+	}
+	return rs, nil
+}
+
+func (r *Rule) compile() error {
+	if r.Match == "" {
+		return fmt.Errorf("empty match")
+	}
+	if r.From != "" && !IsValidScript(r.From) {
+		return fmt.Errorf("invalid source script: %s", r.From)
+	}
+	if r.To != "" && !IsValidScript(r.To) {
+		return fmt.Errorf("invalid target script: %s", r.To)
+	}
+	if r.Lang != "" {
+		stdLang, ok := IsValidISO639(r.Lang)
+		if !ok {
+			return fmt.Errorf("\"%s\" isn't a ISO-639 language code", r.Lang)
+		}
+		r.Lang = stdLang
+	}
+	if r.Regex {
+		re, err := regexp.Compile(r.Match)
+		if err != nil {
+			return err
+		}
+		r.re = re
+	}
+	return nil
+}
+
+// LoadRules reads a JSON array of rules
+func LoadRules(r io.Reader) (*RuleSet, error) {
+	var rules []Rule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to decode rules: %w", err)
+	}
+	return NewRuleSet(rules...)
+}
+
+// LoadRulesFile reads a JSON rule file
+func LoadRulesFile(path string) (*RuleSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rs, err := LoadRules(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rs, nil
+}
+
+// LoadRulesFS reads every rule file of fsys matching pattern (e.g. an embed.FS
+// and "rules/*.json"), concatenated in lexical order of the file names
+func LoadRulesFS(fsys fs.FS, pattern string) (*RuleSet, error) {
+	paths, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no rule file matches %s", pattern)
+	}
+
+	all := &RuleSet{}
+	for _, path := range paths {
+		f, err := fsys.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		rs, err := LoadRules(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		all.rules = append(all.rules, rs.rules...)
+	}
+	return all, nil
+}
+
+// Len returns the number of rules
+func (rs *RuleSet) Len() int {
+	return len(rs.rules)
+}
+
+// Apply runs the rules in scope for from → to and the language on text.
+// trace, if not nil, is called for every rule that changed the text.
+func (rs *RuleSet) Apply(text string, from, to Script, lang string, trace func(RuleTrace)) string {
+	if rs == nil {
+		return text
+	}
+	for _, r := range rs.rules {
+		if !r.inScope(from, to, lang) {
+			continue
+		}
+		var out string
+		if r.re != nil {
+			out = r.re.ReplaceAllString(text, r.Replace)
+		} else {
+			out = strings.ReplaceAll(text, r.Match, r.Replace)
+		}
+		if out != text && trace != nil {
+			trace(RuleTrace{Rule: r.Name, Before: text, After: out})
+		}
+		text = out
+	}
+	return text
+}
+
+func (r Rule) inScope(from, to Script, lang string) bool {
+	if r.From != "" && r.From != from {
+		return false
+	}
+	if r.To != "" && r.To != to {
+		return false
+	}
+	if r.Lang != "" {
+		stdLang, _ := IsValidISO639(lang)
+		if stdLang != r.Lang {
+			return false
+		}
+	}
+	return true
+}
+
+// WithRules makes the manager rewrite every conversion with rs after the backend responds
+func WithRules(rs *RuleSet) ManagerOption {
+	return func(am *AksharamukhaManager) {
+		am.rules = rs
+	}
+}
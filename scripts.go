@@ -1,9 +1,9 @@
 package aksharamukha
 
-import "slices"
 // IsValidScript checks if a script is valid
 func IsValidScript(s Script) bool {
-	return slices.Contains(validScripts, s)
+	_, ok := scriptRegistry[s]
+	return ok
 }
 
 
@@ -153,5 +153,4 @@ const (
 	ZanabazarSquare Script = "ZanabazarSquare"
 )
 
-var validScripts = []Script{"Ahom", "Arab", "Ariyaka", "Assamese", "Avestan", "Balinese", "BatakKaro", "BatakManda", "BatakPakpak", "BatakSima", "BatakToba", "Bengali", "Bhaiksuki", "Brahmi", "Buginese", "Buhid", "Burmese", "Chakma", "Cham", "RussianCyrillic", "Devanagari", "Dogra", "Elym", "Ethi", "GunjalaGondi", "MasaramGondi", "Grantha", "GranthaPandya", "Gujarati", "Hanunoo", "Hatr", "Hebrew", "Hebr-Ar", "Armi", "Phli", "Prti", "Hiragana", "Katakana", "Javanese", "Kaithi", "Kannada", "Kawi", "KhamtiShan", "Kharoshthi", "Khmer", "Khojki", "KhomThai", "Khudawadi", "Lao", "LaoPali", "Lepcha", "Limbu", "Mahajani", "Makasar", "Malayalam", "Mani", "Marchen", "MeeteiMayek", "Modi", "Mon", "Mongolian", "Mro", "Multani", "Nbat", "Nandinagari", "Newa", "Narb", "OldPersian", "Sogo", "Sarb", "Oriya", "Pallava", "Palm", "Arab-Fa", "PhagsPa", "Phnx", "Phlp", "Gurmukhi", "Ranjana", "Rejang", "HanifiRohingya", "BarahaNorth", "BarahaSouth", "RomanColloquial", "PersianDMG", "HK", "IAST", "IASTPali", "IPA", "ISO", "ISOPali", "ISO233", "ISO259", "Itrans", "IASTLOC", "RomanReadable", "HebrewSBL", "SLP1", "Type", "Latn", "Titus", "Velthuis", "WX", "Samr", "Santali", "Saurashtra", "Shahmukhi", "Shan", "Sharada", "Siddham", "Sinhala", "Sogd", "SoraSompeng", "Soyombo", "Sundanese", "SylotiNagri", "Syrn", "Syre", "Syrj", "Tagalog", "Tagbanwa", "TaiLaing", "Takri", "Tamil", "TamilExtended", "TamilBrahmi", "Telugu", "Thaana", "Thai", "TaiTham", "LaoTham", "KhuenTham", "LueTham", "Tibetan", "Tirhuta", "Ugar", "Urdu", "Vatteluttu", "Wancho", "WarangCiti", "ZanabazarSquare"}
 
@@ -0,0 +1,204 @@
+package aksharamukha
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Segment is a run of text in a single script. Script is empty for runs without
+// letters (digits, symbols, emoji…). Offset is the byte offset of the run in the text.
+type Segment struct {
+	Text   string
+	Script Script
+	Offset int
+}
+
+// groupIndex maps each script to its detection group, scripts without ranges are
+// absent. The romanization schemes, which detection credits to Latn, share its group.
+var groupIndex = func() map[Script]int {
+	m := make(map[Script]int)
+	for i, g := range detectGroups {
+		for _, s := range g.scripts {
+			m[s] = i
+		}
+	}
+	for _, info := range scriptTable {
+		if _, ok := m[info.Script]; !ok && info.Category.Has(CategoryRoman) {
+			m[info.Script] = m[Latn]
+		}
+	}
+	return m
+}()
+
+// runeGroup returns the detection group of r, -1 for characters belonging to no
+// script. Digits and punctuation of a script's own block, such as the danda,
+// belong to it.
+func runeGroup(r rune) int {
+	if !unicode.In(r, unicode.L, unicode.M, unicode.Nd, unicode.P) {
+		return -1
+	}
+	for i, g := range detectGroups {
+		if inRanges(r, g.ranges) {
+			return i
+		}
+	}
+	return -1
+}
+
+// joinsRun reports whether r continues the run it follows whatever its code point:
+// combining marks and the zero-width (non-)joiners
+func joinsRun(r rune) bool {
+	return r == '‌' || r == '‍' || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r)
+}
+
+// bridgesRun reports whether r, found between two runs of the same script, is
+// kept inside a single run rather than splitting it
+func bridgesRun(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r)
+}
+
+// SegmentByScript splits text into runs of a single script. Spaces and punctuation
+// between two runs of the same script stay inside the run; combining marks and
+// zero-width joiners follow the letter they attach to. Concatenating the Text of
+// the segments gives back text.
+func SegmentByScript(text string) []Segment {
+	type run struct {
+		start, end int
+		group      int
+	}
+	var runs []run
+	for i, r := range text {
+		g := runeGroup(r)
+		if len(runs) > 0 && joinsRun(r) {
+			g = runs[len(runs)-1].group
+		}
+		end := i + len(string(r))
+		if len(runs) > 0 && runs[len(runs)-1].group == g {
+			runs[len(runs)-1].end = end
+			continue
+		}
+		runs = append(runs, run{i, end, g})
+	}
+
+	// absorb spaces and punctuation sitting between two runs of the same group
+	for i := 1; i+1 < len(runs); i++ {
+		prev, cur, next := runs[i-1], runs[i], runs[i+1]
+		if cur.group != -1 || prev.group == -1 || prev.group != next.group ||
+			strings.IndexFunc(text[cur.start:cur.end], func(r rune) bool { return !bridgesRun(r) }) >= 0 {
+			continue
+		}
+		runs[i-1].end = next.end
+		runs = append(runs[:i], runs[i+2:]...)
+		i--
+	}
+
+	segments := make([]Segment, len(runs))
+	for i, r := range runs {
+		seg := Segment{Text: text[r.start:r.end], Offset: r.start}
+		if r.group != -1 {
+			seg.Script = detectGroups[r.group].scripts[0]
+			for _, guess := range DetectScript(seg.Text) {
+				if groupIndex[guess.Script] == r.group {
+					seg.Script = guess.Script
+					break
+				}
+			}
+		}
+		segments[i] = seg
+	}
+	return segments
+}
+
+// TranslitSpansWithContext converts only the runs of text written in the from
+// script using the default manager, see TranslitSpans
+func TranslitSpansWithContext(ctx context.Context, text string, from, to Script, opts TranslitOptions) (string, error) {
+	mgr, err := getOrCreateDefaultManager(ctx)
+	if err != nil {
+		return "", err
+	}
+	return translitSpans(ctx, mgr, text, from, to, opts)
+}
+
+// TranslitSpans converts only the runs of text written in the from script (or in
+// a script sharing its Unicode block) and leaves everything else, such as English
+// words, digits, URLs or emoji, byte-for-byte intact. If from is empty, the
+// dominant script of text is used. A romanization scheme such as IAST reads every
+// Latin run, since code points cannot tell it from English: set opts.SkipSpan to
+// keep some words as they are. Each run goes through the manager's middleware.
+func (am *AksharamukhaManager) TranslitSpans(ctx context.Context, text string, from, to Script, opts TranslitOptions) (string, error) {
+	return translitSpans(ctx, am, text, from, to, opts)
+}
+
+func translitSpans(ctx context.Context, t Transliterator, text string, from, to Script, opts TranslitOptions) (string, error) {
+	if err := checkSource(text, from); err != nil {
+		return "", err
+	}
+	if from == "" {
+		if from = detectSource(text); from == "" {
+			return "", fmt.Errorf("could not detect the source script")
+		}
+	}
+	group, ok := groupIndex[from]
+	if !ok {
+		return "", fmt.Errorf("script %s has no Unicode ranges to segment by", from)
+	}
+
+	var b strings.Builder
+	converted := make(map[string]string)
+	segments := SegmentByScript(text)
+	if opts.SkipSpan != nil && from.IsRoman() {
+		segments = splitWords(segments)
+	}
+	for _, seg := range segments {
+		if seg.Script == "" || groupIndex[seg.Script] != group ||
+			opts.SkipSpan != nil && opts.SkipSpan(seg) {
+			b.WriteString(seg.Text)
+			continue
+		}
+		out, ok := converted[seg.Text]
+		if !ok {
+			var err error
+			if out, err = t.Translit(ctx, seg.Text, from, to, opts); err != nil {
+				return "", fmt.Errorf("span at byte %d: %w", seg.Offset, err)
+			}
+			converted[seg.Text] = out
+		}
+		b.WriteString(out)
+	}
+	return b.String(), nil
+}
+
+// splitWords breaks the Latin segments into their words, the text between the
+// words is left without a script
+func splitWords(segments []Segment) []Segment {
+	var out []Segment
+	for _, seg := range segments {
+		if seg.Script != Latn {
+			out = append(out, seg)
+			continue
+		}
+		start, inWord := 0, false
+		for i, r := range seg.Text {
+			if isWord := unicode.In(r, unicode.L, unicode.M); isWord != inWord {
+				if i > start {
+					out = append(out, wordSegment(seg, start, i, inWord))
+				}
+				start, inWord = i, isWord
+			}
+		}
+		if start < len(seg.Text) {
+			out = append(out, wordSegment(seg, start, len(seg.Text), inWord))
+		}
+	}
+	return out
+}
+
+func wordSegment(seg Segment, start, end int, word bool) Segment {
+	part := Segment{Text: seg.Text[start:end], Offset: seg.Offset + start}
+	if word {
+		part.Script = seg.Script
+	}
+	return part
+}
@@ -1,6 +1,7 @@
 package aksharamukha
 
-// TODO add STDERR warning for these partially supported languages
+// indicSubset lists the scripts the backend only handles for their Indic/Pali
+// subset, Translit reports them with WarnIndicSubset (see the script registry)
 var indicSubset = []string{"LaoTham", "LueTham", "KhuenTham", "PhagsPa", "TaiLaing", "Mon", "Ahom", "KhamtiShan", "Khmer", "Burmese", "Lao", "Thai", "Balinese", "Javanese", "Tibetan", "LaoPali", "TaiTham", "Cham", "Lepcha", "Ahom", "ZanabazarSquare"}
 
 var Lang2Scripts = map[string][]string{
@@ -56,7 +57,7 @@ var Lang2Scripts = map[string][]string{
 	"jav": {"Javanese"},                      // Javanese
 	"bug": {"Buginese"},                      // Buginese
 	"syl": {"SylotiNagri"},                   // Sylheti
-	"bho": {"Devanagari"},                    // additional: "Kaithi" // Bhojpuri
+	"bho": {"Devanagari", "Kaithi"},          // Bhojpuri
 	"awa": {"Devanagari"},                    // Awadhi
 	"kok": {"Devanagari"},                    // Konkani
 	"dgo": {"Devanagari"},                    // Dogri
@@ -70,19 +71,19 @@ var Lang2Scripts = map[string][]string{
 	"div": {"Thaana"},                        // Dhivehi
 
 	// Ancient or historic languages
-	"san": {"Devanagari"},                   // additional: "Grantha", "GranthaPandya", "Kharoshthi", "Nandinagari", "Ranjana", "Sharada", "Siddham", "Brahmi" // Sanskrit
+	"san": {"Devanagari", "Grantha", "GranthaPandya", "Kharoshthi", "Nandinagari", "Ranjana", "Sharada", "Siddham", "Brahmi"}, // Sanskrit
 	"ave": {"Avestan"},                      // Avestan
-	"pal": {"Phli"},                         // additional: "Phlp" // Pahlavi
+	"pal": {"Phli", "Phlp"},                 // Pahlavi
 	"xpr": {"Prti"},                         // Parthian
 	"xna": {"Narb"},                         // Old North Arabian
 	"xsa": {"Sarb"},                         // Old South Arabian
 	"peo": {"OldPersian"},                   // Old Persian
-	"sog": {"Sogd"},                         // additional: "Sogo" // Sogdian
+	"sog": {"Sogd", "Sogo"},                 // Sogdian
 	"arc": {"Armi"},                         // Imperial Aramaic
 	"phn": {"Phnx"},                         // Phoenician
 	"smp": {"Samr"},                         // Samaritan
 	"uga": {"Ugar"},                         // Ugaritic
-	"syr": {"Syre"},                         // additional: "Syrn", "Syrj" // Syriac
+	"syr": {"Syre", "Syrn", "Syrj"},         // Syriac
 
 	// Languages with limited script usage data
 	"aha": {"Ahom"},                         // Ahom
@@ -0,0 +1,115 @@
+package aksharamukha
+
+import (
+	"context"
+	"fmt"
+)
+
+// RomanStyle selects the flavour of romanization
+type RomanStyle int
+
+const (
+	// StyleAcademic uses the language's profile scheme or Script2RomanScheme, with full diacritics
+	StyleAcademic RomanStyle = iota
+	// StyleReadable targets learners: RomanReadable, without most diacritics
+	StyleReadable
+	// StyleASCII produces plain ASCII for search and URLs
+	StyleASCII
+	// StyleIPA transcribes the pronunciation in the International Phonetic Alphabet
+	StyleIPA
+)
+
+func (s RomanStyle) String() string {
+	switch s {
+	case StyleAcademic:
+		return "academic"
+	case StyleReadable:
+		return "readable"
+	case StyleASCII:
+		return "ascii"
+	case StyleIPA:
+		return "ipa"
+	}
+	return fmt.Sprintf("RomanStyle(%d)", int(s))
+}
+
+// RomanStyled romanizes text of the given language in the requested style.
+//
+// The readable and IPA schemes are only available for Indic sources; for the
+// other scripts, and for languages whose profile does not override the style
+// (see RomanProfile.Styles), styles fall back in this order:
+//
+//	StyleIPA      → IPA, else StyleAcademic
+//	StyleReadable → RomanReadable, else StyleAcademic
+//	StyleASCII    → StyleReadable with diacritics removed, else StyleAcademic with diacritics removed
+//
+// Each fallback is reported as a WarnStyleFallback warning.
+func RomanStyled(ctx context.Context, text, languageCode string, style RomanStyle) (string, error) {
+	return RomanStyledWithOptions(ctx, text, languageCode, style, DefaultOptions())
+}
+
+// RomanStyledWithOptions is RomanStyled with transliteration options
+func RomanStyledWithOptions(ctx context.Context, text, languageCode string, style RomanStyle, opts TranslitOptions) (string, error) {
+	mgr, err := getOrCreateDefaultManager(ctx)
+	if err != nil {
+		return "", err
+	}
+	return romanStyled(ctx, mgr, text, languageCode, style, opts)
+}
+
+// RomanStyled romanizes text in the requested style using this manager's profiles
+func (am *AksharamukhaManager) RomanStyled(ctx context.Context, text, languageCode string, style RomanStyle, opts TranslitOptions) (string, error) {
+	return romanStyled(ctx, am, text, languageCode, style, opts)
+}
+
+func romanStyled(ctx context.Context, t Transliterator, text, languageCode string, style RomanStyle, opts TranslitOptions) (string, error) {
+	res, err := romanDetailed(ctx, t, text, languageCode, style, opts)
+	if err != nil {
+		return "", err
+	}
+	return res.Text, nil
+}
+
+// styleTarget picks the scheme and extra post-options of a style, following the
+// fallback order documented on RomanStyled
+func styleTarget(style RomanStyle, source, academic Script, profile RomanProfile, opts TranslitOptions) (Script, []PostOption) {
+	if scheme, ok := profile.Styles[style]; ok {
+		if style == StyleASCII {
+			return scheme, []PostOption{PostRemoveDiacritics}
+		}
+		return scheme, nil
+	}
+
+	fallback := func(to RomanStyle) {
+		opts.warn(Warning{
+			Kind:    WarnStyleFallback,
+			Message: fmt.Sprintf("%s romanization is not available for %s, using %s", style, source, to),
+		})
+	}
+
+	switch style {
+	case StyleReadable:
+		if indicSource(source) {
+			return RomanReadable, nil
+		}
+		fallback(StyleAcademic)
+	case StyleASCII:
+		if indicSource(source) {
+			return RomanReadable, []PostOption{PostRemoveDiacritics}
+		}
+		fallback(StyleAcademic)
+		return academic, []PostOption{PostRemoveDiacritics}
+	case StyleIPA:
+		if indicSource(source) {
+			return IPA, nil
+		}
+		fallback(StyleAcademic)
+	}
+	return academic, nil
+}
+
+// indicSource reports whether the backend's Indic conversion engine handles the
+// script, which the readable and IPA schemes require
+func indicSource(s Script) bool {
+	return s.Category().Has(CategoryBrahmic)
+}
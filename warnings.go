@@ -0,0 +1,67 @@
+package aksharamukha
+
+import (
+	"fmt"
+	"slices"
+)
+
+// WarningKind classifies non-fatal problems found while preparing or running a conversion
+type WarningKind int
+
+const (
+	// WarnUnknownOption: a pre- or post-option is not in the catalog
+	WarnUnknownOption WarningKind = iota
+	// WarnInapplicableOption: an option does not fit the source or target script
+	WarnInapplicableOption
+	// WarnStyleFallback: the requested RomanStyle is unavailable and another one was used
+	WarnStyleFallback
+	// WarnIndicSubset: the source or target script is only handled for its Indic/Pali subset
+	WarnIndicSubset
+	// WarnLowQualityPair: the capability matrix rates the script pair lossy or unsupported
+	WarnLowQualityPair
+)
+
+func (k WarningKind) String() string {
+	switch k {
+	case WarnUnknownOption:
+		return "unknown-option"
+	case WarnInapplicableOption:
+		return "inapplicable-option"
+	case WarnStyleFallback:
+		return "style-fallback"
+	case WarnIndicSubset:
+		return "indic-subset"
+	case WarnLowQualityPair:
+		return "low-quality-pair"
+	}
+	return "unknown"
+}
+
+// Warning is a non-fatal problem reported to TranslitOptions.OnWarning
+type Warning struct {
+	Kind    WarningKind
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Kind.String() + ": " + w.Message
+}
+
+// warn hands w to the caller's warning handler, if any, unless its kind is suppressed
+func (opts TranslitOptions) warn(w Warning) {
+	if opts.OnWarning != nil && !slices.Contains(opts.SuppressWarnings, w.Kind) {
+		opts.OnWarning(w)
+	}
+}
+
+// warnIndicSubset reports the scripts of the pair the backend only handles partially
+func warnIndicSubset(from, to Script, opts TranslitOptions) {
+	for _, s := range []Script{from, to} {
+		if info, ok := s.Info(); ok && info.Support == SupportIndicSubset {
+			opts.warn(Warning{
+				Kind:    WarnIndicSubset,
+				Message: fmt.Sprintf("%s is only supported for its Indic/Pali subset, native orthography may be mangled", s),
+			})
+		}
+	}
+}